@@ -0,0 +1,107 @@
+package build
+
+import (
+	"github.com/pelletier/go-toml/v2"
+	"os"
+	"path/filepath"
+)
+
+// cacheVersion is part of every unit hash, bumping it invalidates all cached units.
+const cacheVersion = "1"
+
+// Cache remembers the hashes of the units compiled by previous builds, units whose hash did not change reuse their
+// IR and object files from the build folder.
+type Cache struct {
+	path string
+
+	units   map[string]string
+	pending map[string]string
+	used    map[string]bool
+	reused  int
+}
+
+type cacheFile struct {
+	Units map[string]string
+}
+
+// LoadCache reads the cache of the build folder, a missing or invalid cache is treated as empty.
+func LoadCache(dir string) *Cache {
+	c := &Cache{
+		path:    filepath.Join(dir, "cache.toml"),
+		units:   make(map[string]string),
+		pending: make(map[string]string),
+		used:    make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return c
+	}
+
+	var file cacheFile
+
+	if toml.Unmarshal(data, &file) == nil && file.Units != nil {
+		c.units = file.Units
+	}
+
+	return c
+}
+
+// Reuse returns true if the unit was built with the same hash before and all of its outputs still exist. Otherwise
+// the unit needs to be rebuilt, it is removed from the cache and its new hash is only recorded by Commit.
+func (c *Cache) Reuse(unit, hash string, outputs ...string) bool {
+	c.used[unit] = true
+
+	if c.units[unit] == hash {
+		reuse := true
+
+		for _, output := range outputs {
+			if _, err := os.Stat(output); err != nil {
+				reuse = false
+				break
+			}
+		}
+
+		if reuse {
+			c.reused++
+			return true
+		}
+	}
+
+	delete(c.units, unit)
+	c.pending[unit] = hash
+
+	return false
+}
+
+// Commit records the hashes of the rebuilt units, it is called once their outputs were compiled successfully.
+func (c *Cache) Commit() {
+	for unit, hash := range c.pending {
+		c.units[unit] = hash
+	}
+
+	clear(c.pending)
+}
+
+// Reused returns the number of units reused by this build.
+func (c *Cache) Reused() int {
+	return c.reused
+}
+
+// Save writes the cache to the build folder, units which were not part of this build or were not committed are dropped.
+func (c *Cache) Save() error {
+	file := cacheFile{Units: make(map[string]string)}
+
+	for unit, hash := range c.units {
+		if c.used[unit] {
+			file.Units[unit] = hash
+		}
+	}
+
+	data, err := toml.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, data, 0640)
+}
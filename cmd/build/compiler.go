@@ -3,39 +3,108 @@ package build
 import (
 	"bytes"
 	"errors"
+	"fireball/core/target"
+	"fireball/core/utils"
+	"fireball/core/workspace"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
+	"slices"
 	"strings"
 )
 
 type Compiler struct {
 	OptimizationLevel int
 
+	// Keep frame pointers and emit debug info for C sources
+	DebugInfo bool
+
+	// Machine the executable is compiled for
+	Target target.Target
+
+	// External tools used to compile and link, see ResolveToolchain
+	Toolchain *Toolchain
+
+	// Kind of file the inputs are linked to
+	Output workspace.OutputKind
+
+	// Maximum number of IR files compiled in parallel, zero uses one per CPU
+	Jobs int
+
 	inputs    []string
+	compiled  []string
 	libraries []string
+
+	libraryPaths []string
+	linkArgs     []string
+	objects      []string
+	cSources     []cSource
+}
+
+// cSource is a C file compiled to an object file which is linked together with the compiled IR.
+type cSource struct {
+	path   string
+	object string
 }
 
 func (c *Compiler) AddInput(input string) {
 	c.inputs = append(c.inputs, input)
 }
 
+// AddCompiledInput adds an IR file whose object file from a previous build is still up to date, it is only recompiled
+// when optimizing.
+func (c *Compiler) AddCompiledInput(input string) {
+	c.inputs = append(c.inputs, input)
+	c.compiled = append(c.compiled, input)
+}
+
 func (c *Compiler) AddLibrary(library string) {
 	c.libraries = append(c.libraries, library)
 }
 
+// AddLibraryPath adds a folder searched for libraries when linking.
+func (c *Compiler) AddLibraryPath(path string) {
+	c.libraryPaths = append(c.libraryPaths, path)
+}
+
+// AddLinkArgs adds arguments passed to the linker after all inputs.
+func (c *Compiler) AddLinkArgs(args ...string) {
+	c.linkArgs = append(c.linkArgs, args...)
+}
+
+// AddObject adds an object file or static library which is linked into the output.
+func (c *Compiler) AddObject(object string) {
+	c.objects = append(c.objects, object)
+}
+
+// AddCSource adds a C file which is compiled to the object file and linked into the output.
+func (c *Compiler) AddCSource(path, object string) {
+	c.cSources = append(c.cSources, cSource{path: path, object: object})
+	c.objects = append(c.objects, object)
+}
+
 func (c *Compiler) Compile(output string) error {
+	// C sources do not depend on the IR
+	if err := c.compileCSources(); err != nil {
+		return err
+	}
+
 	if c.OptimizationLevel == 0 {
 		// Compile each IR file individually
-		for _, input := range c.inputs {
-			err := c.compileIr(input)
-			if err != nil {
-				return err
+		errs := make([]error, len(c.inputs))
+
+		utils.Parallel(c.Jobs, len(c.inputs), func(i int) {
+			if !slices.Contains(c.compiled, c.inputs[i]) {
+				errs[i] = c.compileIr(c.inputs[i])
 			}
+		})
+
+		if err := errors.Join(errs...); err != nil {
+			return err
 		}
 
-		return c.linkExecutable(c.inputs, output)
+		return c.link(c.inputs, output)
 	}
 
 	// Link all IR files together
@@ -56,12 +125,27 @@ func (c *Compiler) Compile(output string) error {
 		return err
 	}
 
-	return c.linkExecutable([]string{ir}, output)
+	return c.link([]string{ir}, output)
+}
+
+func (c *Compiler) link(inputs []string, output string) error {
+	if err := c.Toolchain.CheckLink(c.Output); err != nil {
+		return err
+	}
+
+	switch c.Output {
+	case workspace.StaticLibraryOutput:
+		return c.archive(inputs, output)
+	case workspace.SharedLibraryOutput:
+		return c.linkShared(inputs, output)
+	default:
+		return c.linkExecutable(inputs, output)
+	}
 }
 
 func (c *Compiler) linkIr(output string) error {
 	// Create command
-	cmd := exec.Command("llvm-link")
+	cmd := exec.Command(c.Toolchain.LlvmLink.Path)
 
 	for _, input := range c.inputs {
 		cmd.Args = append(cmd.Args, input)
@@ -76,7 +160,7 @@ func (c *Compiler) linkIr(output string) error {
 
 func (c *Compiler) optimizeIr(input string) error {
 	// Create command
-	cmd := exec.Command("opt", input, fmt.Sprintf("-O%d", c.OptimizationLevel), "-o", withExtension(input, "bc"))
+	cmd := exec.Command(c.Toolchain.Opt.Path, input, fmt.Sprintf("-O%d", c.OptimizationLevel), "-mtriple", c.Target.Triple(), "-o", withExtension(input, "bc"))
 
 	// Execute
 	return execute(cmd)
@@ -84,13 +168,19 @@ func (c *Compiler) optimizeIr(input string) error {
 
 func (c *Compiler) compileIr(input string) error {
 	// Create command
-	cmd := exec.Command("llc", input, fmt.Sprintf("-O%d", c.OptimizationLevel))
+	cmd := exec.Command(c.Toolchain.Llc.Path, input, fmt.Sprintf("-O%d", c.OptimizationLevel), "-mtriple", c.Target.Triple())
 
-	if c.OptimizationLevel == 0 {
+	if c.DebugInfo {
 		cmd.Args = append(cmd.Args, "--frame-pointer")
 		cmd.Args = append(cmd.Args, "all")
 	}
 
+	// Libraries can end up in position independent executables or be loaded at any address
+	if c.Output != workspace.ExecutableOutput {
+		cmd.Args = append(cmd.Args, "--relocation-model")
+		cmd.Args = append(cmd.Args, "pic")
+	}
+
 	cmd.Args = append(cmd.Args, "--filetype")
 	cmd.Args = append(cmd.Args, "obj")
 
@@ -101,37 +191,110 @@ func (c *Compiler) compileIr(input string) error {
 	return execute(cmd)
 }
 
-//goland:noinspection GoBoolExpressions
+func (c *Compiler) compileCSources() error {
+	if len(c.cSources) == 0 {
+		return nil
+	}
+
+	if err := c.Toolchain.CheckC(); err != nil {
+		return fmt.Errorf("CSources: %w", err)
+	}
+
+	errs := make([]error, len(c.cSources))
+
+	utils.Parallel(c.Jobs, len(c.cSources), func(i int) {
+		source := c.cSources[i]
+
+		// Create command
+		cmd := exec.Command(c.Toolchain.CCompiler.Path, c.Toolchain.CCompiler.Args...)
+
+		cmd.Args = append(cmd.Args, "-c", source.path)
+		cmd.Args = append(cmd.Args, fmt.Sprintf("-O%d", c.OptimizationLevel))
+
+		if c.DebugInfo {
+			cmd.Args = append(cmd.Args, "-g")
+		}
+
+		if c.Output != workspace.ExecutableOutput {
+			cmd.Args = append(cmd.Args, "-fPIC")
+		}
+
+		cmd.Args = append(cmd.Args, "-o", source.object)
+
+		// Execute
+		if err := execute(cmd); err != nil {
+			errs[i] = fmt.Errorf("CSources: failed to compile '%s'\n%w", source.path, err)
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
 func (c *Compiler) linkExecutable(inputs []string, output string) error {
 	// Create command
-	cmd := exec.Command(getLinker(), "-L/usr/lib")
+	cmd := exec.Command(c.Toolchain.Linker.Path)
 
-	switch runtime.GOOS {
+	switch c.Target.OS {
 	case "linux":
+		libraries := c.Toolchain.Crt.Path
+
+		cmd.Args = append(cmd.Args, "-L"+libraries)
+
+		cmd.Args = append(cmd.Args, "-m")
+		cmd.Args = append(cmd.Args, getEmulation(c.Target))
+
 		cmd.Args = append(cmd.Args, "-dynamic-linker")
-		cmd.Args = append(cmd.Args, "/lib64/ld-linux-x86-64.so.2")
+		cmd.Args = append(cmd.Args, getDynamicLinker(c.Target))
 
-		cmd.Args = append(cmd.Args, "/usr/lib/crt1.o")
-		cmd.Args = append(cmd.Args, "/usr/lib/crti.o")
+		cmd.Args = append(cmd.Args, filepath.Join(libraries, "crt1.o"))
+		cmd.Args = append(cmd.Args, filepath.Join(libraries, "crti.o"))
 
 	case "darwin":
+		cmd.Args = append(cmd.Args, "-L/usr/lib")
+
 		cmd.Args = append(cmd.Args, "-dynamic")
 		cmd.Args = append(cmd.Args, "-syslibroot")
 		cmd.Args = append(cmd.Args, "/Library/Developer/CommandLineTools/SDKs/MacOSX.sdk")
 	}
 
-	for _, library := range c.libraries {
-		cmd.Args = append(cmd.Args, "-l"+library)
-	}
+	c.addLinkInputs(cmd, inputs)
 
-	for _, input := range inputs {
-		cmd.Args = append(cmd.Args, withExtension(input, "o"))
+	if c.Target.OS == "linux" {
+		cmd.Args = append(cmd.Args, filepath.Join(c.Toolchain.Crt.Path, "crtn.o"))
 	}
 
-	if runtime.GOOS == "linux" {
-		cmd.Args = append(cmd.Args, "/usr/lib/crtn.o")
+	cmd.Args = append(cmd.Args, "-o")
+	cmd.Args = append(cmd.Args, output)
+
+	// Execute
+	return execute(cmd)
+}
+
+func (c *Compiler) linkShared(inputs []string, output string) error {
+	// Create command
+	cmd := exec.Command(c.Toolchain.Linker.Path)
+
+	switch c.Target.OS {
+	case "linux":
+		cmd.Args = append(cmd.Args, "-shared")
+
+		if c.Toolchain.Crt.Path != "" {
+			cmd.Args = append(cmd.Args, "-L"+c.Toolchain.Crt.Path)
+		}
+
+		cmd.Args = append(cmd.Args, "-m")
+		cmd.Args = append(cmd.Args, getEmulation(c.Target))
+
+	case "darwin":
+		cmd.Args = append(cmd.Args, "-L/usr/lib")
+
+		cmd.Args = append(cmd.Args, "-dylib")
+		cmd.Args = append(cmd.Args, "-syslibroot")
+		cmd.Args = append(cmd.Args, "/Library/Developer/CommandLineTools/SDKs/MacOSX.sdk")
 	}
 
+	c.addLinkInputs(cmd, inputs)
+
 	cmd.Args = append(cmd.Args, "-o")
 	cmd.Args = append(cmd.Args, output)
 
@@ -139,12 +302,56 @@ func (c *Compiler) linkExecutable(inputs []string, output string) error {
 	return execute(cmd)
 }
 
+func (c *Compiler) archive(inputs []string, output string) error {
+	// Create command, the archive is recreated so objects of removed files do not linger
+	cmd := exec.Command(c.Toolchain.Archiver.Path, "rcs", output)
+
+	for _, input := range inputs {
+		cmd.Args = append(cmd.Args, withExtension(input, "o"))
+	}
+
+	// Libraries are linked by the users of the archive
+	cmd.Args = append(cmd.Args, c.objects...)
+
+	// Execute
+	if err := os.Remove(output); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return execute(cmd)
+}
+
+// addLinkInputs adds the object files, libraries and additional arguments to a linker command. Libraries come after the
+// objects using them.
+func (c *Compiler) addLinkInputs(cmd *exec.Cmd, inputs []string) {
+	for _, input := range inputs {
+		cmd.Args = append(cmd.Args, withExtension(input, "o"))
+	}
+
+	cmd.Args = append(cmd.Args, c.objects...)
+
+	for _, path := range c.libraryPaths {
+		cmd.Args = append(cmd.Args, "-L"+path)
+	}
+
+	for _, library := range c.libraries {
+		cmd.Args = append(cmd.Args, "-l"+library)
+	}
+
+	cmd.Args = append(cmd.Args, c.linkArgs...)
+}
+
 func execute(cmd *exec.Cmd) error {
 	stderr := bytes.Buffer{}
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
 
+	// The process state is nil if the command could not be started
+	if cmd.ProcessState == nil {
+		return fmt.Errorf("failed to run '%s': %w", cmd.Path, err)
+	}
+
 	if !cmd.ProcessState.Success() {
 		return errors.New(stderr.String())
 	}
@@ -152,16 +359,29 @@ func execute(cmd *exec.Cmd) error {
 	return err
 }
 
-func getLinker() string {
-	switch runtime.GOOS {
-	case "linux":
-		return "ld.lld"
-
-	case "darwin":
-		return "ld"
+func getEmulation(t target.Target) string {
+	switch t.Arch {
+	case "aarch64":
+		return "aarch64linux"
+	case "i686":
+		return "elf_i386"
+	case "armv7":
+		return "armelf_linux_eabi"
+	default:
+		return "elf_x86_64"
+	}
+}
 
+func getDynamicLinker(t target.Target) string {
+	switch t.Arch {
+	case "aarch64":
+		return "/lib/ld-linux-aarch64.so.1"
+	case "i686":
+		return "/lib/ld-linux.so.2"
+	case "armv7":
+		return "/lib/ld-linux-armhf.so.3"
 	default:
-		panic("Unknown operating system: " + runtime.GOOS)
+		return "/lib64/ld-linux-x86-64.so.2"
 	}
 }
 
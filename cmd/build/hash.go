@@ -0,0 +1,209 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fireball/core/ast"
+	"fireball/core/types"
+	"fireball/core/workspace"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+	"strings"
+)
+
+// HashFile returns the cache hash of a file. It covers the text of the file, the declarations of other files it
+// depends on and the key, which needs to contain all build settings that change the emitted code.
+func HashFile(file *workspace.File, key string) string {
+	h := sha256.New()
+
+	_, _ = io.WriteString(h, cacheVersion+"\n")
+	_, _ = io.WriteString(h, key+"\n")
+	_, _ = io.WriteString(h, file.Text+"\n")
+
+	d := newDependencies(file)
+
+	for _, decl := range file.Decls {
+		d.AcceptDecl(decl)
+	}
+
+	d.write(h)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashString returns the cache hash of a unit generated from a string.
+func HashString(text, key string) string {
+	h := sha256.New()
+
+	_, _ = io.WriteString(h, cacheVersion+"\n")
+	_, _ = io.WriteString(h, key+"\n")
+	_, _ = io.WriteString(h, text)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// dependencies collects the declarations of other files used by a file. Only the parts of a declaration which
+// influence the code emitted for its users are hashed, so changes to function bodies do not invalidate other files.
+type dependencies struct {
+	file *workspace.File
+
+	// File of every declaration in the project and its dependencies
+	types     map[string]*workspace.File
+	functions map[*ast.Func]*workspace.File
+
+	signatures map[string]string
+}
+
+func newDependencies(file *workspace.File) *dependencies {
+	d := &dependencies{
+		file:       file,
+		types:      make(map[string]*workspace.File),
+		functions:  make(map[*ast.Func]*workspace.File),
+		signatures: make(map[string]string),
+	}
+
+	for _, project := range file.Project.Projects() {
+		for _, file := range project.Files {
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.Struct:
+					d.types[decl.QualifiedName()] = file
+				case *ast.Enum:
+					d.types[decl.QualifiedName()] = file
+				case *ast.Func:
+					d.functions[decl] = file
+				case *ast.Impl:
+					for _, function := range decl.Functions {
+						d.functions[function.(*ast.Func)] = file
+					}
+				}
+			}
+		}
+	}
+
+	return d
+}
+
+func (d *dependencies) write(h hash.Hash) {
+	names := make([]string, 0, len(d.signatures))
+
+	for name := range d.signatures {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		_, _ = io.WriteString(h, d.signatures[name]+"\n")
+	}
+}
+
+func (d *dependencies) addType(type_ types.Type) {
+	var typeName string
+
+	switch type_ := type_.(type) {
+	case *ast.Struct:
+		typeName = type_.QualifiedName()
+	case *ast.Enum:
+		typeName = type_.QualifiedName()
+	default:
+		type_.AcceptTypes(d)
+		return
+	}
+
+	name := "type " + typeName
+
+	if _, ok := d.signatures[name]; ok {
+		return
+	}
+
+	// Mark as visited before visiting the fields, structs can point to themselves
+	d.signatures[name] = ""
+	signature := strings.Builder{}
+
+	switch type_ := type_.(type) {
+	case *ast.Struct:
+		for _, field := range type_.StaticFields {
+			signature.WriteString(fmt.Sprintf(" static %s %s", field.Name, field.Type))
+		}
+
+		for _, field := range type_.Fields {
+			signature.WriteString(fmt.Sprintf(" %s %s %t", field.Name, field.Type, field.Embedded))
+		}
+
+		// The layout also depends on the types of the fields
+		type_.AcceptTypes(d)
+
+	case *ast.Enum:
+		signature.WriteString(" " + type_.Type.String())
+
+		for _, case_ := range type_.Cases {
+			signature.WriteString(fmt.Sprintf(" %s=%d", case_.Name, case_.Value))
+		}
+	}
+
+	d.signatures[name] = d.signature(d.types[typeName], name, signature.String())
+}
+
+func (d *dependencies) addFunction(function *ast.Func) {
+	name := "func " + function.MangledName()
+
+	if _, ok := d.signatures[name]; ok {
+		return
+	}
+
+	signature := fmt.Sprintf(" %s %d", function.Signature(false), function.Flags)
+
+	for _, attribute := range function.Attributes {
+		signature += fmt.Sprintf(" #%s%v", attribute.Name, attribute.Value)
+	}
+
+	d.signatures[name] = d.signature(d.functions[function], name, signature)
+
+	function.AcceptTypes(d)
+}
+
+// signature returns the signature of a declaration, declarations of the file itself are covered by its text.
+func (d *dependencies) signature(file *workspace.File, name, signature string) string {
+	if file == d.file {
+		return name
+	}
+
+	return name + signature
+}
+
+// types.Visitor
+
+func (d *dependencies) VisitType(type_ types.Type) {
+	if type_ != nil {
+		d.addType(type_)
+	}
+}
+
+// ast.Acceptor
+
+func (d *dependencies) AcceptDecl(decl ast.Decl) {
+	decl.AcceptTypes(d)
+	decl.AcceptChildren(d)
+}
+
+func (d *dependencies) AcceptStmt(stmt ast.Stmt) {
+	stmt.AcceptTypes(d)
+	stmt.AcceptChildren(d)
+}
+
+func (d *dependencies) AcceptExpr(expr ast.Expr) {
+	expr.AcceptTypes(d)
+
+	if expr.Result().Type != nil {
+		d.addType(expr.Result().Type)
+	}
+
+	if expr.Result().Function != nil {
+		d.addFunction(expr.Result().Function)
+	}
+
+	expr.AcceptChildren(d)
+}
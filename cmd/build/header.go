@@ -0,0 +1,334 @@
+package build
+
+import (
+	"fireball/core/ast"
+	"fireball/core/types"
+	"fireball/core/workspace"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// WriteHeader writes a C header declaring the functions exported by the project together with the structs and enums
+// used by them.
+func WriteHeader(project *workspace.Project, writer io.Writer) error {
+	h := header{
+		visited: make(map[string]bool),
+		defined: make(map[string]bool),
+	}
+
+	// Collect
+	for _, file := range project.SortedFiles() {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.Func:
+				h.addFunction(decl)
+
+			case *ast.Impl:
+				for _, function := range decl.Functions {
+					h.addFunction(function.(*ast.Func))
+				}
+			}
+		}
+	}
+
+	// Write
+	guard := headerGuard(project.Config.Name)
+
+	h.line("// Generated by fireball from project '%s', do not edit.", project.Config.Name)
+	h.line("")
+	h.line("#ifndef %s", guard)
+	h.line("#define %s", guard)
+	h.line("")
+	h.line("#include <stdbool.h>")
+	h.line("#include <stdint.h>")
+	h.line("")
+	h.line("#ifdef __cplusplus")
+	h.line("extern \"C\" {")
+	h.line("#endif")
+
+	for _, enum := range h.enums {
+		h.writeEnum(enum)
+	}
+
+	if len(h.structs) > 0 {
+		h.line("")
+
+		for _, struct_ := range h.structs {
+			h.line("typedef struct %s %s;", cName(struct_.QualifiedName()), cName(struct_.QualifiedName()))
+		}
+
+		// Fields of other structs stored by value need to be defined first
+		for _, struct_ := range h.structs {
+			h.writeStruct(struct_)
+		}
+	}
+
+	for _, function := range h.functions {
+		h.line("")
+		h.docs(function.Docs)
+
+		params := make([]string, 0, len(function.Params))
+
+		for _, param := range function.Params {
+			params = append(params, declarator(param.Type, param.Name.Lexeme))
+		}
+
+		h.line("%s;", declarator(function.Returns, fmt.Sprintf("%s(%s)", function.MangledName(), paramList(params, false))))
+	}
+
+	h.line("")
+	h.line("#ifdef __cplusplus")
+	h.line("}")
+	h.line("#endif")
+	h.line("")
+	h.line("#endif // %s", guard)
+
+	_, err := io.WriteString(writer, h.text.String())
+	return err
+}
+
+type header struct {
+	functions []*ast.Func
+	structs   []*ast.Struct
+	enums     []*ast.Enum
+
+	// Names of the collected and written types, the same type can be referenced by different copies
+	visited map[string]bool
+	defined map[string]bool
+
+	text strings.Builder
+}
+
+func (h *header) addFunction(function *ast.Func) {
+	var export types.ExportAttribute
+
+	if !function.GetAttribute(&export) {
+		return
+	}
+
+	h.functions = append(h.functions, function)
+
+	for _, param := range function.Params {
+		h.addType(param.Type)
+	}
+
+	h.addType(function.Returns)
+}
+
+func (h *header) addType(type_ types.Type) {
+	switch type_ := type_.(type) {
+	case *ast.Struct:
+		if h.visited[type_.QualifiedName()] {
+			return
+		}
+
+		h.visited[type_.QualifiedName()] = true
+		h.structs = append(h.structs, type_)
+
+		for _, field := range type_.Fields {
+			h.addType(field.Type)
+		}
+
+	case *ast.Enum:
+		if h.visited[type_.QualifiedName()] {
+			return
+		}
+
+		h.visited[type_.QualifiedName()] = true
+		h.enums = append(h.enums, type_)
+
+	case *ast.Func:
+		for _, param := range type_.Params {
+			h.addType(param.Type)
+		}
+
+		h.addType(type_.Returns)
+
+	case *types.PointerType:
+		h.addType(type_.Pointee)
+
+	case *types.ArrayType:
+		h.addType(type_.Base)
+	}
+}
+
+func (h *header) writeEnum(enum *ast.Enum) {
+	h.line("")
+	h.docs(enum.Docs)
+	name := cName(enum.QualifiedName())
+	h.line("typedef %s %s;", declarator(enum.Type, ""), name)
+
+	for _, case_ := range enum.Cases {
+		h.line("#define %s_%s ((%s) %d)", name, case_.Name, name, case_.Value)
+	}
+}
+
+func (h *header) writeStruct(struct_ *ast.Struct) {
+	if h.defined[struct_.QualifiedName()] {
+		return
+	}
+
+	h.defined[struct_.QualifiedName()] = true
+
+	for _, field := range struct_.Fields {
+		if dependency, ok := valueStruct(field.Type); ok {
+			h.writeStruct(dependency)
+		}
+	}
+
+	h.line("")
+	h.docs(struct_.Docs)
+	h.line("struct %s {", cName(struct_.QualifiedName()))
+
+	for _, field := range struct_.Fields {
+		h.line("    %s;", declarator(field.Type, field.Name.Lexeme))
+	}
+
+	h.line("};")
+}
+
+func (h *header) docs(docs string) {
+	if docs == "" {
+		return
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(docs), "\n") {
+		h.line("// %s", strings.TrimSpace(line))
+	}
+}
+
+func (h *header) line(format string, args ...any) {
+	h.text.WriteString(strings.TrimRight(fmt.Sprintf(format, args...), " "))
+	h.text.WriteRune('\n')
+}
+
+// valueStruct returns the struct stored directly in a value of the type, without a pointer in between.
+func valueStruct(type_ types.Type) (*ast.Struct, bool) {
+	switch type_ := type_.(type) {
+	case *ast.Struct:
+		return type_, true
+	case *types.ArrayType:
+		return valueStruct(type_.Base)
+	default:
+		return nil, false
+	}
+}
+
+// declarator returns the C declaration of a value with the given name and type, an empty name declares the type only.
+func declarator(type_ types.Type, name string) string {
+	switch type_ := type_.(type) {
+	case *types.PrimitiveType:
+		return withName(primitiveName(type_.Kind), name)
+
+	case *ast.Struct:
+		return withName(cName(type_.QualifiedName()), name)
+
+	case *ast.Enum:
+		return withName(cName(type_.QualifiedName()), name)
+
+	case *types.PointerType:
+		switch pointee := type_.Pointee.(type) {
+		case *types.ArrayType:
+			return declarator(pointee, "(*"+name+")")
+		default:
+			return declarator(pointee, "*"+name)
+		}
+
+	case *types.ArrayType:
+		return declarator(type_.Base, fmt.Sprintf("%s[%d]", name, type_.Count))
+
+	case *ast.Func:
+		// Bound methods are a pair of the function and the instance
+		if type_.IsBound() {
+			return withName("struct { void *function; void *instance; }", name)
+		}
+
+		params := make([]string, 0, len(type_.Params))
+
+		for _, param := range type_.Params {
+			params = append(params, declarator(param.Type, ""))
+		}
+
+		return declarator(type_.Returns, fmt.Sprintf("(*%s)(%s)", name, paramList(params, type_.IsVariadic())))
+
+	default:
+		panic("build.declarator() - Invalid type")
+	}
+}
+
+// cName returns the C name of a struct or enum, types of dependencies are prefixed with their namespace.
+func cName(name string) string {
+	return strings.ReplaceAll(name, ".", "_")
+}
+
+func withName(type_, name string) string {
+	if name == "" {
+		return type_
+	}
+
+	return type_ + " " + name
+}
+
+func paramList(params []string, variadic bool) string {
+	if variadic {
+		params = append(params, "...")
+	}
+
+	if len(params) == 0 {
+		return "void"
+	}
+
+	return strings.Join(params, ", ")
+}
+
+func primitiveName(kind types.PrimitiveKind) string {
+	switch kind {
+	case types.Void:
+		return "void"
+	case types.Bool:
+		return "bool"
+
+	case types.U8:
+		return "uint8_t"
+	case types.U16:
+		return "uint16_t"
+	case types.U32:
+		return "uint32_t"
+	case types.U64:
+		return "uint64_t"
+
+	case types.I8:
+		return "int8_t"
+	case types.I16:
+		return "int16_t"
+	case types.I32:
+		return "int32_t"
+	case types.I64:
+		return "int64_t"
+
+	case types.F32:
+		return "float"
+	case types.F64:
+		return "double"
+
+	default:
+		panic("build.primitiveName() - Invalid primitive kind")
+	}
+}
+
+func headerGuard(name string) string {
+	guard := strings.Builder{}
+
+	for _, char := range name {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) {
+			guard.WriteRune(unicode.ToUpper(char))
+		} else {
+			guard.WriteRune('_')
+		}
+	}
+
+	guard.WriteString("_H")
+	return guard.String()
+}
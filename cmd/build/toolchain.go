@@ -0,0 +1,414 @@
+package build
+
+import (
+	"errors"
+	"fireball/core/target"
+	"fireball/core/workspace"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Newest and oldest LLVM versions probed for versioned binary names like 'llc-17'. The emitted IR uses opaque pointers
+// and the 'Min' module flag behavior, which older versions can't read.
+const (
+	newestLlvmVersion = 21
+	minLlvmVersion    = 15
+)
+
+// toolKind decides how a tool is searched for and checked.
+type toolKind uint8
+
+const (
+	// Only searched for by its name
+	plainTool toolKind = iota
+
+	// Also searched for with LLVM version suffixes
+	llvmTool
+
+	// LLVM tool reading the emitted IR, versions older than minLlvmVersion are skipped
+	llvmIrTool
+)
+
+var llvmVersionRegex = regexp.MustCompile(`LLVM version (\d+)\.`)
+
+// Tool is an external program or folder used to compile and link the emitted IR.
+type Tool struct {
+	Name string
+
+	// Resolved path, empty if the tool was not found
+	Path string
+
+	// Arguments passed to the tool before any others, e.g. the target of clang
+	Args []string
+
+	// Where the path came from, an environment variable, project.toml, the PATH or a C compiler
+	Source string
+
+	// Reason why the tool could not be resolved
+	Err error
+}
+
+// Toolchain contains the external tools needed to build a project for a target.
+type Toolchain struct {
+	LlvmLink Tool
+	Opt      Tool
+	Llc      Tool
+	Linker   Tool
+	Archiver Tool
+
+	// Only needed for projects with CSources
+	CCompiler Tool
+
+	// Folder containing crt1.o, crti.o and crtn.o, only needed on Linux
+	Crt Tool
+}
+
+// ResolveToolchain searches for the tools needed to build for the target. Paths set by environment variables take
+// precedence over paths set in project.toml, tools without either are searched for in the PATH.
+func ResolveToolchain(config workspace.ToolchainConfig, t target.Target) *Toolchain {
+	tc := &Toolchain{
+		LlvmLink: resolveTool("llvm-link", "FIREBALL_LLVM_LINK", "LlvmLink", config.LlvmLink, llvmIrTool),
+		Opt:      resolveTool("opt", "FIREBALL_OPT", "Opt", config.Opt, llvmIrTool),
+		Llc:      resolveTool("llc", "FIREBALL_LLC", "Llc", config.Llc, llvmIrTool),
+		Archiver: resolveTool("llvm-ar", "FIREBALL_ARCHIVER", "Archiver", config.Archiver, llvmTool, "ar"),
+
+		CCompiler: resolveCCompiler(config.CCompiler, t),
+	}
+
+	switch t.OS {
+	case "linux":
+		tc.Linker = resolveTool("ld.lld", "FIREBALL_LINKER", "Linker", config.Linker, llvmTool)
+		tc.Crt = resolveCrt(config.Crt, t)
+
+	case "darwin":
+		tc.Linker = resolveTool("ld", "FIREBALL_LINKER", "Linker", config.Linker, plainTool)
+
+	default:
+		tc.Linker = Tool{
+			Name: "linker",
+			Err:  fmt.Errorf("linking executables for '%s' is not supported", t),
+		}
+	}
+
+	return tc
+}
+
+// Tools returns all tools of the toolchain.
+func (tc *Toolchain) Tools() []*Tool {
+	tools := []*Tool{&tc.LlvmLink, &tc.Opt, &tc.Llc, &tc.Linker, &tc.Archiver, &tc.CCompiler}
+
+	if tc.Crt.Name != "" {
+		tools = append(tools, &tc.Crt)
+	}
+
+	return tools
+}
+
+// Check returns the errors of all tools needed for a build.
+func (tc *Toolchain) Check(optimizing bool, output workspace.OutputKind) error {
+	return errors.Join(tc.CheckCompile(optimizing), tc.CheckLink(output))
+}
+
+// CheckCompile returns the errors of the tools needed to compile IR to object files, llvm-link and opt are only needed
+// when optimizing.
+func (tc *Toolchain) CheckCompile(optimizing bool) error {
+	if optimizing {
+		return errors.Join(tc.LlvmLink.Err, tc.Opt.Err, tc.Llc.Err)
+	}
+
+	return tc.Llc.Err
+}
+
+// CheckC returns the error of the C compiler, it is only needed to compile CSources.
+func (tc *Toolchain) CheckC() error {
+	return tc.CCompiler.Err
+}
+
+// CheckLink returns the errors of the tools needed to link or archive object files to the output.
+func (tc *Toolchain) CheckLink(output workspace.OutputKind) error {
+	switch output {
+	case workspace.StaticLibraryOutput:
+		return tc.Archiver.Err
+	case workspace.SharedLibraryOutput:
+		return tc.Linker.Err
+	default:
+		return errors.Join(tc.Linker.Err, tc.Crt.Err)
+	}
+}
+
+// Version returns the line printed by the tool for --version containing the version, or the first line if there is
+// none. Empty for folders or failing tools.
+func (t *Tool) Version() string {
+	if t.Path == "" || t.Name == "crt" {
+		return ""
+	}
+
+	output, err := exec.Command(t.Path, append(t.Args, "--version")...).CombinedOutput()
+	if err != nil {
+		return ""
+	}
+
+	first := ""
+
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+
+		if first == "" {
+			first = line
+		}
+
+		if strings.Contains(line, "version") {
+			return line
+		}
+	}
+
+	return first
+}
+
+// checkLlvmVersion returns an error if the LLVM tool at the path is older than minLlvmVersion, tools whose version
+// can't be determined are accepted.
+func checkLlvmVersion(name, path string) error {
+	output, err := exec.Command(path, "--version").CombinedOutput()
+	if err != nil {
+		return nil
+	}
+
+	match := llvmVersionRegex.FindSubmatch(output)
+	if match == nil {
+		return nil
+	}
+
+	version, _ := strconv.Atoi(string(match[1]))
+
+	if version < minLlvmVersion {
+		return fmt.Errorf("%s '%s' is LLVM %d which is too old, LLVM %d or newer is required", name, path, version, minLlvmVersion)
+	}
+
+	return nil
+}
+
+// resolveTool resolves a tool, LLVM tools are also searched for with version suffixes and fallbacks are searched for
+// last. Tools reading the IR prefer the newest versioned binary if the one without a suffix is too old.
+func resolveTool(name, env, key, configured string, kind toolKind, fallbacks ...string) Tool {
+	tool := Tool{Name: name}
+
+	if path := os.Getenv(env); path != "" {
+		tool.Path = path
+		tool.Source = env
+	} else if configured != "" {
+		tool.Path = configured
+		tool.Source = "project.toml"
+	}
+
+	// Explicit path
+	if tool.Path != "" {
+		path, err := exec.LookPath(tool.Path)
+
+		if err != nil {
+			tool.Err = fmt.Errorf("%s '%s' set by %s was not found", name, tool.Path, tool.Source)
+			tool.Path = ""
+		} else if kind == llvmIrTool {
+			if err := checkLlvmVersion(name, path); err != nil {
+				tool.Err = fmt.Errorf("%w, set by %s", err, tool.Source)
+				tool.Path = ""
+			} else {
+				tool.Path = path
+			}
+		} else {
+			tool.Path = path
+		}
+
+		return tool
+	}
+
+	// Search PATH
+	candidates := []string{name}
+
+	if kind != plainTool {
+		for version := newestLlvmVersion; version >= minLlvmVersion; version-- {
+			candidates = append(candidates, fmt.Sprintf("%s-%d", name, version))
+		}
+	}
+
+	candidates = append(candidates, fallbacks...)
+
+	var tooOld error
+
+	for _, candidate := range candidates {
+		path, err := exec.LookPath(candidate)
+		if err != nil {
+			continue
+		}
+
+		if kind == llvmIrTool {
+			if err := checkLlvmVersion(name, path); err != nil {
+				if tooOld == nil {
+					tooOld = err
+				}
+
+				continue
+			}
+		}
+
+		tool.Path = path
+		tool.Source = "PATH"
+
+		return tool
+	}
+
+	if tooOld != nil {
+		tool.Err = fmt.Errorf("%w, install a newer LLVM or set %s or Toolchain.%s in project.toml", tooOld, env, key)
+		return tool
+	}
+
+	tool.Err = fmt.Errorf("%s was not found in the PATH, install it or set %s or Toolchain.%s in project.toml", name, env, key)
+	return tool
+}
+
+func resolveCrt(configured string, t target.Target) Tool {
+	tool := Tool{Name: "crt"}
+
+	if path := os.Getenv("FIREBALL_CRT"); path != "" {
+		tool.Path = path
+		tool.Source = "FIREBALL_CRT"
+	} else if configured != "" {
+		tool.Path = configured
+		tool.Source = "project.toml"
+	}
+
+	// Explicit path
+	if tool.Path != "" {
+		if !hasCrt(tool.Path) {
+			tool.Err = fmt.Errorf("folder '%s' set by %s does not contain crt1.o", tool.Path, tool.Source)
+			tool.Path = ""
+		}
+
+		return tool
+	}
+
+	// Ask the system compiler
+	for _, compiler := range getCCompilers(t) {
+		output, err := exec.Command(compiler[0], append(compiler[1:], "-print-file-name=crt1.o")...).Output()
+		if err != nil {
+			continue
+		}
+
+		// Compilers print the plain file name if they do not know where it is
+		path := strings.TrimSpace(string(output))
+
+		if filepath.IsAbs(path) {
+			dir := filepath.Dir(filepath.Clean(path))
+
+			if hasCrt(dir) {
+				tool.Path = dir
+				tool.Source = strings.Join(compiler, " ")
+
+				return tool
+			}
+		}
+	}
+
+	// Common folders
+	for _, dir := range getCrtDirs(t) {
+		if hasCrt(dir) {
+			tool.Path = dir
+			tool.Source = "default"
+
+			return tool
+		}
+	}
+
+	tool.Err = fmt.Errorf("crt1.o for '%s' was not found, install a C toolchain for the target or set FIREBALL_CRT or Toolchain.Crt in project.toml", t)
+	return tool
+}
+
+// resolveCCompiler resolves the C compiler, without an explicit path the first compiler of getCCompilers found in the
+// PATH is used.
+func resolveCCompiler(configured string, t target.Target) Tool {
+	tool := Tool{Name: "cc"}
+
+	if path := os.Getenv("FIREBALL_CC"); path != "" {
+		tool.Path = path
+		tool.Source = "FIREBALL_CC"
+	} else if configured != "" {
+		tool.Path = configured
+		tool.Source = "project.toml"
+	}
+
+	// Explicit path
+	if tool.Path != "" {
+		path, err := exec.LookPath(tool.Path)
+
+		if err != nil {
+			tool.Err = fmt.Errorf("cc '%s' set by %s was not found", tool.Path, tool.Source)
+			tool.Path = ""
+		} else {
+			tool.Path = path
+		}
+
+		return tool
+	}
+
+	// Search PATH
+	for _, compiler := range getCCompilers(t) {
+		if path, err := exec.LookPath(compiler[0]); err == nil {
+			tool.Path = path
+			tool.Args = compiler[1:]
+			tool.Source = "PATH"
+
+			return tool
+		}
+	}
+
+	tool.Err = fmt.Errorf("no C compiler for '%s' was found in the PATH, install one or set FIREBALL_CC or Toolchain.CCompiler in project.toml", t)
+	return tool
+}
+
+func hasCrt(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "crt1.o"))
+	return err == nil
+}
+
+// getCCompilers returns the C compilers, including their arguments, which are asked for the location of crt1.o.
+func getCCompilers(t target.Target) [][]string {
+	var compilers [][]string
+
+	if t == target.Host() {
+		if cc := strings.Fields(os.Getenv("CC")); len(cc) > 0 {
+			compilers = append(compilers, cc)
+		}
+
+		compilers = append(compilers, []string{"cc"}, []string{"gcc"})
+	}
+
+	compilers = append(compilers, []string{getGnuTriple(t) + "-gcc"})
+	compilers = append(compilers, []string{"clang", "--target=" + t.Triple()})
+
+	return compilers
+}
+
+// getCrtDirs returns the folders searched for crt1.o when no C compiler knows its location, cross compiling uses the
+// layout of the Debian cross toolchains.
+func getCrtDirs(t target.Target) []string {
+	if t == target.Host() {
+		return []string{filepath.Join("/usr/lib", getGnuTriple(t)), "/usr/lib64", "/usr/lib"}
+	}
+
+	return []string{filepath.Join("/usr", getGnuTriple(t), "lib")}
+}
+
+// getGnuTriple returns the triple used by GNU toolchains, e.g. 'arm-linux-gnueabihf'.
+func getGnuTriple(t target.Target) string {
+	arch := t.Arch
+
+	if arch == "armv7" {
+		arch = "arm"
+	}
+
+	return fmt.Sprintf("%s-%s-%s", arch, t.OS, t.Environment)
+}
@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fireball/core/llvm"
+	"fireball/core/scanner"
+	"fireball/core/types"
+	"fireball/core/workspace"
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var benchTime time.Duration
+var saveBaseline bool
+
+func GetBenchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench [pattern]",
+		Short: "Build and run the benchmarks of the project.",
+		Long:  "Build and run the functions marked with #[Bench] and compare their time per call against the baseline in the build folder. A pattern only runs the benchmarks whose name contains it, or matches it if it contains '*', '?' or '['.",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   benchCmd,
+	}
+
+	addBuildFlags(cmd)
+
+	cmd.Flags().DurationVar(&benchTime, "time", time.Second, "Minimum time a benchmark is measured for, the iteration count is increased until it is reached.")
+	cmd.Flags().BoolVar(&saveBaseline, "save-baseline", false, "Save the results as the new baseline. (default = only if there is no baseline)")
+
+	return cmd
+}
+
+func benchCmd(cmd *cobra.Command, args []string) {
+	pattern := ""
+	if len(args) > 0 {
+		pattern = args[0]
+	}
+
+	// Benchmarks of debug builds are meaningless, -O still overrides the optimization level of the profile
+	if profileName == "" {
+		profileName = "release"
+	}
+
+	// Build
+	var benchmarks []test
+
+	_, output := buildProject(cmd, func(project *workspace.Project) artifact {
+		benchmarks = getTests(project, pattern, &types.BenchAttribute{})
+		return getBenchArtifact(project, benchmarks)
+	})
+
+	// Run
+	baselinePath := filepath.Join(filepath.Dir(output), "bench-baseline.toml")
+	baseline, hasBaseline := loadBaseline(baselinePath)
+
+	fmt.Printf("\nrunning %d benchmarks\n\n", len(benchmarks))
+
+	results, err := runBenchmarks(output, benchmarks, baseline)
+
+	if err != nil {
+		var exitErr *exec.ExitError
+
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			os.Exit(exitErr.ExitCode())
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(4)
+	}
+
+	// Save baseline, results of benchmarks which did not run are kept
+	if saveBaseline || !hasBaseline {
+		for name, nsPerOp := range results {
+			baseline[name] = nsPerOp
+		}
+
+		if err := saveBaselineFile(baselinePath, baseline); err != nil {
+			log.Fatalln(err.Error())
+		}
+
+		fmt.Printf("\nSaved baseline to '%s'\n", baselinePath)
+	}
+}
+
+// getBenchArtifact returns an executable timing the benchmarks, it replaces the entrypoint calling main so libraries
+// can be benchmarked too.
+func getBenchArtifact(project *workspace.Project, benchmarks []test) artifact {
+	text := strings.Builder{}
+	text.WriteString(benchTime.String() + "\n")
+
+	for _, benchmark := range benchmarks {
+		text.WriteString(benchmark.name + " " + benchmark.function.MangledName() + "\n")
+	}
+
+	return artifact{
+		output:         workspace.ExecutableOutput,
+		name:           project.Config.Name + "-bench",
+		entrypointName: "__bench",
+		entrypointText: text.String(),
+		emitEntrypoint: func(path string) error {
+			return generateBenchRunner(project, benchmarks, path)
+		},
+	}
+}
+
+// runBenchmarks runs the benchmark executable, prints each result compared to the baseline as soon as it is reported
+// and returns the nanoseconds per call of every benchmark.
+func runBenchmarks(output string, benchmarks []test, baseline map[string]float64) (map[string]float64, error) {
+	run := exec.Command(output)
+
+	run.Stdin = os.Stdin
+	run.Stderr = os.Stderr
+
+	stdout, err := run.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := run.Start(); err != nil {
+		return nil, err
+	}
+
+	width := 0
+
+	for _, benchmark := range benchmarks {
+		width = max(width, len(benchmark.name))
+	}
+
+	// Each line is '<name>\t<iterations>\t<nanoseconds>'
+	results := make(map[string]float64)
+	lines := bufio.NewScanner(stdout)
+
+	for lines.Scan() {
+		parts := strings.Split(lines.Text(), "\t")
+		if len(parts) != 3 {
+			fmt.Println(lines.Text())
+			continue
+		}
+
+		iterations, _ := strconv.ParseInt(parts[1], 10, 64)
+		nanoseconds, _ := strconv.ParseInt(parts[2], 10, 64)
+
+		if iterations <= 0 {
+			continue
+		}
+
+		nsPerOp := float64(nanoseconds) / float64(iterations)
+		results[parts[0]] = nsPerOp
+
+		fmt.Printf("%-*s  %12d  %14.2f ns/op  ", width, parts[0], iterations, nsPerOp)
+		printBaselineChange(nsPerOp, baseline, parts[0])
+	}
+
+	return results, run.Wait()
+}
+
+// printBaselineChange prints the change of the time per call compared to the baseline, changes within the noise of a
+// few percent are not highlighted.
+func printBaselineChange(nsPerOp float64, baseline map[string]float64, name string) {
+	previous, ok := baseline[name]
+
+	if !ok || previous <= 0 {
+		fmt.Println("new")
+		return
+	}
+
+	change := (nsPerOp - previous) / previous * 100
+	text := fmt.Sprintf("%+.2f%%", change)
+
+	switch {
+	case change > 5:
+		_, _ = color.New(color.FgRed).Println(text)
+	case change < -5:
+		_, _ = color.New(color.FgGreen).Println(text)
+	default:
+		fmt.Println(text)
+	}
+}
+
+// baselineFile contains the nanoseconds per call of every benchmark saved by a previous run.
+type baselineFile struct {
+	NsPerOp map[string]float64
+}
+
+// loadBaseline reads the baseline, a missing or invalid baseline is treated as empty.
+func loadBaseline(path string) (map[string]float64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return make(map[string]float64), false
+	}
+
+	var file baselineFile
+
+	if toml.Unmarshal(data, &file) != nil || file.NsPerOp == nil {
+		return make(map[string]float64), false
+	}
+
+	return file.NsPerOp, true
+}
+
+func saveBaselineFile(path string, baseline map[string]float64) error {
+	data, err := toml.Marshal(baselineFile{NsPerOp: baseline})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0640)
+}
+
+// generateBenchRunner generates a main function timing every benchmark with a monotonic clock. The iteration count
+// starts at 1 and grows until a run takes at least the bench time, the last run is reported to stdout. Benchmarks are
+// called through a volatile function pointer, so the optimizer can't inline them into the loop and remove the calls.
+func generateBenchRunner(project *workspace.Project, benchmarks []test, path string) error {
+	// Create module
+	m := llvm.NewModule(project.Target)
+	m.Source("__bench")
+
+	if !project.Profile.DebugInfo {
+		m.StripDebugInfo()
+	}
+
+	void := m.Void()
+	i32 := m.Primitive("i32", 32, llvm.SignedEncoding)
+	i64 := m.Primitive("i64", 64, llvm.SignedEncoding)
+	long := m.Primitive("long", project.Target.PointerSize()*8, llvm.SignedEncoding)
+	ptr := m.Pointer("*u8", m.Primitive("u8", 8, llvm.UnsignedEncoding))
+	longPtr := m.Pointer("*long", long)
+	timespec := m.Array("timespec", 2, long)
+	benchPtr := m.Pointer("*bench", m.Function("bench", []llvm.Type{}, false, void))
+
+	fflush := m.Declare(m.Function("fflush", []llvm.Type{ptr}, false, i32))
+	printf := m.Declare(m.Function("printf", []llvm.Type{ptr}, true, i32))
+	clockGettime := m.Declare(m.Function("clock_gettime", []llvm.Type{i32, ptr}, false, i32))
+
+	clock := monotonicClock(project)
+
+	// Main
+	main := m.Define(m.Function("main", []llvm.Type{}, false, i32), "_fireball_bench")
+	main.PushScope()
+
+	block := main.Block("")
+
+	call := func(function llvm.Value, args []llvm.Value, returns llvm.Type) llvm.Value {
+		value := block.Call(function, args, returns)
+		value.SetLocation(scanner.Token{})
+
+		return value
+	}
+
+	literal := func(type_ llvm.Type, value int64) llvm.Value {
+		return main.Literal(type_, llvm.Literal{Signed: value})
+	}
+
+	// Timespec fields are C longs, the math is done in 64 bits so it does not overflow on 32-bit targets
+	field := func(time llvm.Value, index int64) llvm.Value {
+		value := block.Load(block.GetElementPtr(time, []llvm.Value{literal(i32, 0), literal(i32, index)}, longPtr, timespec))
+
+		if project.Target.PointerSize() < 8 {
+			return block.Cast(llvm.SExt, value, i64)
+		}
+
+		return value
+	}
+
+	// elapsed returns the nanoseconds between the two timespecs
+	elapsed := func(start, end llvm.Value) llvm.Value {
+		seconds := block.Binary(llvm.Sub, field(end, 0), field(start, 0))
+		nanoseconds := block.Binary(llvm.Sub, field(end, 1), field(start, 1))
+
+		return block.Binary(llvm.Add, block.Binary(llvm.Mul, seconds, literal(i64, 1_000_000_000)), nanoseconds)
+	}
+
+	// Locals
+	iterations := block.Alloca(i64)
+	i := block.Alloca(i64)
+	start := block.Alloca(timespec)
+	end := block.Alloca(timespec)
+	callee := block.Alloca(benchPtr)
+
+	target := literal(i64, benchTime.Nanoseconds())
+
+	// Benchmarks
+	for _, benchmark := range benchmarks {
+		function := m.Declare(m.Function(benchmark.function.MangledName(), []llvm.Type{}, false, void))
+		name := m.Constant(benchmark.name)
+
+		measure := main.Block("bench.measure")
+		loop := main.Block("bench.loop")
+		body := main.Block("bench.body")
+		done := main.Block("bench.done")
+		check := main.Block("bench.check")
+		grow := main.Block("bench.grow")
+		fast := main.Block("bench.fast")
+		slow := main.Block("bench.slow")
+		report := main.Block("bench.report")
+		next := main.Block("bench.next")
+
+		block.Store(callee, function)
+		block.Store(iterations, literal(i64, 1))
+		block.Br(nil, measure, nil)
+
+		// Measure, calls the benchmark the current number of iterations
+		block = measure
+		block.Store(i, literal(i64, 0))
+		call(clockGettime, []llvm.Value{literal(i32, clock), start}, i32)
+		block.Br(nil, loop, nil)
+
+		block = loop
+		block.Br(block.Binary(llvm.Lt, block.Load(i), block.Load(iterations)), body, done)
+
+		block = body
+		call(block.VolatileLoad(callee), []llvm.Value{}, void)
+		block.Store(i, block.Binary(llvm.Add, block.Load(i), literal(i64, 1)))
+		block.Br(nil, loop, nil)
+
+		block = done
+		call(clockGettime, []llvm.Value{literal(i32, clock), end}, i32)
+
+		ns := elapsed(start, end)
+		block.Br(block.Binary(llvm.Lt, ns, target), check, report)
+
+		// Grow, by 10 while far from the bench time and by 2 close to it, up to a billion iterations
+		block = check
+		count := block.Load(iterations)
+		block.Br(block.Binary(llvm.Lt, count, literal(i64, 1_000_000_000)), grow, report)
+
+		block = grow
+		block.Br(block.Binary(llvm.Lt, block.Binary(llvm.Mul, ns, literal(i64, 10)), target), fast, slow)
+
+		block = fast
+		block.Store(iterations, block.Binary(llvm.Mul, count, literal(i64, 10)))
+		block.Br(nil, measure, nil)
+
+		block = slow
+		block.Store(iterations, block.Binary(llvm.Mul, count, literal(i64, 2)))
+		block.Br(nil, measure, nil)
+
+		// Report, flushed so results show up while the next benchmark runs
+		block = report
+		call(printf, []llvm.Value{m.Constant("%s\t%lld\t%lld\n"), name, block.Load(iterations), ns}, i32)
+		call(fflush, []llvm.Value{main.LiteralRaw(ptr, "null")}, i32)
+		block.Br(nil, next, nil)
+
+		block = next
+	}
+
+	block.Ret(literal(i32, 0))
+	main.PopScope()
+
+	// Write module
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	llvm.WriteText(m, file)
+
+	_ = file.Close()
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fireball/core/codegen"
 	"fireball/core/llvm"
 	"fireball/core/scanner"
+	"fireball/core/target"
 	"fireball/core/types"
 	"fireball/core/utils"
 	"fireball/core/workspace"
@@ -14,12 +15,14 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"time"
 )
 
 var opt uint8
+var jobs int
+var targetTriple string
+var profileName string
 
 func GetBuildCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -28,17 +31,64 @@ func GetBuildCmd() *cobra.Command {
 		Run:   buildCmd,
 	}
 
-	cmd.Flags().Uint8VarP(&opt, "opt", "O", 0, "Optimization level. [-O0, -O1, -O2, or -O3] (default = '-O0')")
+	addBuildFlags(cmd)
+	cmd.Flags().StringVar(&targetTriple, "target", "", "Target triple to compile for, e.g. 'aarch64-linux-gnu'. (default = target of project.toml or the host)")
 
 	return cmd
 }
 
-func buildCmd(_ *cobra.Command, _ []string) {
-	buildProject()
+// addBuildFlags adds the flags of the commands which build the project.
+func addBuildFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&profileName, "profile", "", "Build profile, either 'debug', 'release' or one declared in project.toml. (default = 'debug', or 'release' with -O)")
+	cmd.Flags().Uint8VarP(&opt, "opt", "O", 0, "Optimization level, overrides the one of the profile. [-O0, -O1, -O2, or -O3]")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 0, "Maximum number of files processed in parallel. (default = one per CPU)")
 }
 
+func buildCmd(cmd *cobra.Command, _ []string) {
+	buildProject(cmd, getArtifact)
+}
+
+// artifact is the file produced by a build.
+type artifact struct {
+	// Kind of file the project is linked to
+	output workspace.OutputKind
+
+	// File name inside the build folder
+	name string
+
+	// Unit defining the C main function, its text decides if the cached unit can be reused. Only used for executables.
+	entrypointName string
+	entrypointText string
+	emitEntrypoint func(path string) error
+}
+
+// getArtifact returns the executable or library configured in project.toml, executables call the main function.
+func getArtifact(project *workspace.Project) artifact {
+	a := artifact{
+		output: project.Config.Output,
+		name:   getOutputName(project),
+	}
+
+	// Libraries are called from C, they have no entrypoint
+	if a.output == workspace.ExecutableOutput {
+		a.entrypointName = "__entrypoint"
+
+		if function, _ := project.GetFunction("main"); function != nil {
+			a.entrypointText = function.MangledName() + function.Signature(false)
+		}
+
+		a.emitEntrypoint = func(path string) error {
+			return generateEntrypoint(project, path)
+		}
+	}
+
+	return a
+}
+
+// buildProject builds the project in the working directory to the artifact and returns the path of the output.
+//
 //goland:noinspection GoBoolExpressions
-func buildProject() string {
+func buildProject(cmd *cobra.Command, getArtifact func(project *workspace.Project) artifact) (*workspace.Project, string) {
 	start := time.Now()
 
 	// Create project
@@ -47,6 +97,20 @@ func buildProject() string {
 		log.Fatalln(err.Error())
 	}
 
+	project.Profile, err = getProfile(project, cmd.Flags().Changed("opt"))
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	project.Jobs = jobs
+
+	if targetTriple != "" {
+		project.Target, err = target.Parse(targetTriple)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+	}
+
 	// Load files
 	err = project.LoadFiles()
 	if err != nil {
@@ -59,9 +123,11 @@ func buildProject() string {
 		warning: color.New(color.FgYellow),
 	}
 
-	for _, file := range project.Files {
-		for _, diagnostic := range file.FlushDiagnostics() {
-			reporter.Report(file, diagnostic)
+	for _, dependency := range project.Projects() {
+		for _, file := range dependency.SortedFiles() {
+			for _, diagnostic := range file.FlushDiagnostics() {
+				reporter.Report(file, diagnostic)
+			}
 		}
 	}
 
@@ -78,53 +144,165 @@ func buildProject() string {
 		os.Exit(1)
 	}
 
-	// Emit LLVM IR
-	_ = os.Mkdir("build", 0750)
+	a := getArtifact(project)
+
+	// Find toolchain
+	c := build.Compiler{
+		OptimizationLevel: project.Profile.OptimizationLevel,
+		DebugInfo:         project.Profile.DebugInfo,
+		Target:            project.Target,
+		Toolchain:         build.ResolveToolchain(project.Config.Toolchain, project.Target),
+		Output:            a.output,
+		Jobs:              jobs,
+	}
+
+	if err := c.Toolchain.CheckCompile(c.OptimizationLevel > 0); err != nil {
+		log.Fatalf("%s\nRun 'fireball doctor' for more information.\n", err.Error())
+	}
 
-	irPaths := make([]string, 0, len(project.Files))
+	if hasCSources(project) {
+		if err := c.Toolchain.CheckC(); err != nil {
+			log.Fatalf("CSources: %s\nRun 'fireball doctor' for more information.\n", err.Error())
+		}
+	}
+
+	// Emit LLVM IR, every target and profile has its own folder so their artifacts and caches do not replace each
+	// other, builds for the host are kept directly in build/
+	buildPath := filepath.Join(project.Path, "build", project.Profile.Dir)
+
+	if project.Target != target.Host() {
+		buildPath = filepath.Join(project.Path, "build", project.Target.Triple(), project.Profile.Dir)
+	}
+
+	err = os.MkdirAll(buildPath, 0750)
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	cache := build.LoadCache(buildPath)
+	options := getCodegenOptions(project)
+	key := fmt.Sprintf("%d %s %s %+v %v", c.OptimizationLevel, project.Target, c.Output, options, project.Properties())
+
+	// Files of dependencies are compiled and linked together with the project
+	var files []*workspace.File
+
+	for _, dependency := range project.Projects() {
+		files = append(files, dependency.SortedFiles()...)
+	}
+
+	units := make([]*unit, len(files), len(files)+2)
+
+	utils.Parallel(jobs, len(files), func(i int) {
+		file := files[i]
 
-	for _, file := range project.Files {
 		path := strings.ReplaceAll(file.Path, "/", "-")
-		path = filepath.Join(project.Path, "build", path[:len(path)-3]+".ll")
+		if file.Project.Namespace != "" {
+			path = file.Project.Namespace + "-" + path
+		}
+
+		path = filepath.Join(buildPath, path[:len(path)-3]+".ll")
 
-		irFile, _ := os.Create(path)
-		codegen.Emit(file.Path, project, file.Decls, irFile)
-		_ = irFile.Close()
+		units[i] = &unit{
+			path: path,
+			hash: build.HashFile(file, key),
+			emit: func(path string) error {
+				irFile, err := os.Create(path)
+				if err != nil {
+					return err
+				}
 
-		irPaths = append(irPaths, path)
+				codegen.Emit(file.Path, file.Project, file.Decls, options, irFile)
+				return irFile.Close()
+			},
+		}
+	})
+
+	if a.emitEntrypoint != nil {
+		units = append(units, &unit{
+			path: filepath.Join(buildPath, a.entrypointName+".ll"),
+			hash: build.HashString(a.entrypointText, key),
+			emit: a.emitEntrypoint,
+		})
 	}
 
-	entrypointPath := filepath.Join(project.Path, "build", "__entrypoint.ll")
-	irPaths = append(irPaths, entrypointPath)
+	units = append(units, &unit{
+		path: filepath.Join(buildPath, "__runtime.ll"),
+		hash: build.HashString("", key),
+		emit: func(path string) error {
+			return generateRuntime(project.Target, path, options.DebugInfo)
+		},
+	})
+
+	// Reuse cached units, the rest is emitted in parallel
+	for _, unit := range units {
+		outputs := []string{unit.path}
+		if c.OptimizationLevel == 0 {
+			outputs = append(outputs, strings.TrimSuffix(unit.path, ".ll")+".o")
+		}
 
-	err = generateEntrypoint(project, entrypointPath)
+		unit.reused = cache.Reuse(unit.path, unit.hash, outputs...)
+	}
+
+	// Rebuilt units stay out of the saved cache until the build succeeded, so outputs of a failed build are not reused
+	err = cache.Save()
 	if err != nil {
 		log.Fatalln(err.Error())
 	}
 
-	// Compile
-	c := build.Compiler{
-		OptimizationLevel: min(max(int(opt), 0), 3),
+	errs := make([]error, len(units))
+
+	utils.Parallel(jobs, len(units), func(i int) {
+		if !units[i].reused {
+			errs[i] = units[i].emit(units[i].path)
+		}
+	})
+
+	for i, unit := range units {
+		if errs[i] != nil {
+			log.Fatalln(errs[i].Error())
+		}
+
+		if unit.reused {
+			c.AddCompiledInput(unit.path)
+		} else {
+			c.AddInput(unit.path)
+		}
 	}
 
-	for _, irPath := range irPaths {
-		c.AddInput(irPath)
+	// Libraries and objects of the project and its dependencies, paths are relative to the project declaring them
+	for _, dependency := range project.Projects() {
+		addLinkInputs(&c, dependency, buildPath)
 	}
 
-	if runtime.GOOS == "darwin" {
+	// Compile
+	if project.Target.OS == "darwin" {
 		c.AddLibrary("System")
 	} else {
 		c.AddLibrary("m")
 		c.AddLibrary("c")
 	}
 
-	output := filepath.Join(project.Path, "build", project.Config.Name)
+	output := filepath.Join(buildPath, a.name)
 	err = c.Compile(output)
 
 	if err != nil {
 		log.Fatalln(err.Error())
 	}
 
+	if c.Output != workspace.ExecutableOutput {
+		err = generateHeader(project, filepath.Join(buildPath, project.Config.Name+".h"))
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+	}
+
+	cache.Commit()
+
+	err = cache.Save()
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
 	// Print info
 	took := time.Now().Sub(start)
 
@@ -133,18 +311,71 @@ func buildProject() string {
 	}
 
 	_, _ = color.New(color.FgGreen).Print("Build successful")
-	fmt.Printf(", took %s\n", took)
+	fmt.Printf(", took %s, reused %d/%d units\n", took, cache.Reused(), len(units))
 	fmt.Println()
 
 	// Return
-	return output
+	return project, output
+}
+
+// addLinkInputs adds the libraries, objects and C sources declared in project.toml of the project to the compiler.
+func addLinkInputs(c *build.Compiler, project *workspace.Project, buildPath string) {
+	config := project.Config
+
+	for _, library := range config.Libraries {
+		c.AddLibrary(library)
+	}
+
+	for _, path := range config.LibraryPaths {
+		c.AddLibraryPath(filepath.Join(project.Path, path))
+	}
+
+	for _, object := range config.Objects {
+		c.AddObject(filepath.Join(project.Path, object))
+	}
+
+	for _, source := range config.CSources {
+		// Objects keep the .c extension so they do not collide with the objects of Fireball files
+		object := strings.ReplaceAll(filepath.Clean(source), "/", "-") + ".o"
+		if project.Namespace != "" {
+			object = project.Namespace + "-" + object
+		}
+
+		c.AddCSource(filepath.Join(project.Path, source), filepath.Join(buildPath, object))
+	}
+
+	c.AddLinkArgs(config.LinkArgs...)
+}
+
+// hasCSources returns true if the project or one of its dependencies has C sources.
+func hasCSources(project *workspace.Project) bool {
+	for _, dependency := range project.Projects() {
+		if len(dependency.Config.CSources) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unit is a single IR file of the build, reused units are taken from the cache of the previous build.
+type unit struct {
+	path string
+	hash string
+	emit func(path string) error
+
+	reused bool
 }
 
 func generateEntrypoint(project *workspace.Project, path string) error {
 	// Create module
-	m := llvm.NewModule()
+	m := llvm.NewModule(project.Target)
 	m.Source("__entrypoint")
 
+	if !project.Profile.DebugInfo {
+		m.StripDebugInfo()
+	}
+
 	function, _ := project.GetFunction("main")
 
 	void := m.Void()
@@ -190,6 +421,119 @@ func generateEntrypoint(project *workspace.Project, path string) error {
 	return nil
 }
 
+// getOutputName returns the file name of the executable or library the project is compiled to.
+func getOutputName(project *workspace.Project) string {
+	switch project.Config.Output {
+	case workspace.StaticLibraryOutput:
+		return "lib" + project.Config.Name + ".a"
+
+	case workspace.SharedLibraryOutput:
+		if project.Target.OS == "darwin" {
+			return "lib" + project.Config.Name + ".dylib"
+		}
+
+		return "lib" + project.Config.Name + ".so"
+
+	default:
+		return project.Config.Name
+	}
+}
+
+func generateHeader(project *workspace.Project, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = build.WriteHeader(project, file)
+	_ = file.Close()
+
+	return err
+}
+
+// getProfile returns the profile selected by the flags, -O without --profile selects the release profile.
+func getProfile(project *workspace.Project, optChanged bool) (workspace.Profile, error) {
+	name := profileName
+
+	if name == "" {
+		name = "debug"
+
+		if optChanged && opt > 0 {
+			name = "release"
+		}
+	}
+
+	profile, err := project.GetProfile(name)
+	if err != nil {
+		return workspace.Profile{}, err
+	}
+
+	if optChanged {
+		profile.OptimizationLevel = min(int(opt), 3)
+	}
+
+	return profile, nil
+}
+
+func getCodegenOptions(project *workspace.Project) codegen.Options {
+	return codegen.Options{
+		OverflowChecks: project.Profile.OverflowChecks,
+		SafetyChecks:   project.Profile.SafetyChecks,
+		DebugInfo:      project.Profile.DebugInfo,
+	}
+}
+
+func generateRuntime(t target.Target, path string, debugInfo bool) error {
+	// Create module
+	m := llvm.NewModule(t)
+	m.Source("__runtime")
+
+	if !debugInfo {
+		m.StripDebugInfo()
+	}
+
+	void := m.Void()
+	i32 := m.Primitive("i32", 32, llvm.SignedEncoding)
+	ptr := m.Pointer("*u8", m.Primitive("u8", 8, llvm.UnsignedEncoding))
+
+	fflush := m.Declare(m.Function("fflush", []llvm.Type{ptr}, false, i32))
+	dprintf := m.Declare(m.Function("dprintf", []llvm.Type{i32, ptr}, true, i32))
+	abort := m.Declare(m.Function("abort", []llvm.Type{}, false, void))
+
+	// Panic
+	panic_ := m.Define(m.Function(codegen.PanicFunction, []llvm.Type{ptr, ptr, i32, i32}, false, void), "panic")
+	panic_.PushScope()
+	block := panic_.Block("")
+
+	block.Call(fflush, []llvm.Value{panic_.LiteralRaw(ptr, "null")}, i32).SetLocation(scanner.Token{})
+
+	message := block.Call(dprintf, []llvm.Value{
+		panic_.Literal(i32, llvm.Literal{Signed: 2}),
+		m.Constant("panic: %s\n    at %s:%d:%d\n"),
+		panic_.GetParameter(0),
+		panic_.GetParameter(1),
+		panic_.GetParameter(2),
+		panic_.GetParameter(3),
+	}, i32)
+
+	message.SetLocation(scanner.Token{})
+	block.Call(abort, []llvm.Value{}, void).SetLocation(scanner.Token{})
+	block.Unreachable()
+
+	panic_.PopScope()
+
+	// Write module
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	llvm.WriteText(m, file)
+
+	_ = file.Close()
+	return nil
+}
+
 type consoleReporter struct {
 	error   *color.Color
 	warning *color.Color
@@ -204,6 +548,10 @@ func (c *consoleReporter) Report(file *workspace.File, diag utils.Diagnostic) {
 		path = file.Path
 	}
 
+	if file.Project.Namespace != "" {
+		path = file.Project.Namespace + "/" + path
+	}
+
 	msg := fmt.Sprintf("[%s:%d:%d] %s", path, diag.Range.Start.Line, diag.Range.Start.Column+1, diag.Message)
 
 	if diag.Kind == utils.ErrorKind {
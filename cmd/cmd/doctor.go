@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fireball/cmd/build"
+	"fireball/core/target"
+	"fireball/core/workspace"
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"strings"
+)
+
+func GetDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the external tools needed to build projects.",
+		Run:   doctorCmd,
+	}
+
+	cmd.Flags().StringVar(&targetTriple, "target", "", "Target triple to check the tools for. (default = target of project.toml or the host)")
+
+	return cmd
+}
+
+func doctorCmd(_ *cobra.Command, _ []string) {
+	config := workspace.Config{Output: workspace.ExecutableOutput}
+	t := target.Host()
+
+	// Use the project in the working directory if there is one
+	project, err := workspace.NewProject(".")
+
+	if err == nil {
+		config = project.Config
+		t = project.Target
+
+		fmt.Printf("Project:  %s\n", config.Name)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		log.Fatalln(err.Error())
+	}
+
+	if targetTriple != "" {
+		t, err = target.Parse(targetTriple)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+	}
+
+	fmt.Printf("Target:   %s\n", t)
+	fmt.Printf("Output:   %s\n", config.Output)
+	fmt.Println()
+
+	// Tools
+	toolchain := build.ResolveToolchain(config.Toolchain, t)
+
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+
+	for _, tool := range toolchain.Tools() {
+		fmt.Printf("%-10s", tool.Name)
+
+		if tool.Err != nil {
+			_, _ = red.Println(tool.Err.Error())
+			continue
+		}
+
+		_, _ = green.Print(tool.Path)
+		fmt.Printf(" (%s)", tool.Source)
+
+		if version := tool.Version(); version != "" {
+			fmt.Printf(" - %s", version)
+		}
+
+		fmt.Println()
+	}
+
+	fmt.Println()
+
+	// Summary, the C compiler is only needed for projects with C sources
+	var cErr error
+
+	if project != nil && hasCSources(project) {
+		cErr = toolchain.CheckC()
+	}
+
+	printBuildStatus("Debug builds", errors.Join(toolchain.Check(false, config.Output), cErr))
+	printBuildStatus("Optimized builds", errors.Join(toolchain.Check(true, config.Output), cErr))
+}
+
+func printBuildStatus(name string, err error) {
+	fmt.Printf("%s: ", name)
+
+	if err == nil {
+		_, _ = color.New(color.FgGreen).Println("ok")
+		return
+	}
+
+	_, _ = color.New(color.FgRed).Println("will fail")
+
+	for _, line := range strings.Split(err.Error(), "\n") {
+		fmt.Printf("  - %s\n", line)
+	}
+}
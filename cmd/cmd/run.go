@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"fireball/core/workspace"
 	"fmt"
 	"github.com/spf13/cobra"
+	"log"
 	"os"
 	"os/exec"
 )
@@ -14,14 +16,18 @@ func GetRunCmd() *cobra.Command {
 		Run:   runCmd,
 	}
 
-	cmd.Flags().Uint8VarP(&opt, "opt", "O", 0, "Optimization level. [-O0, -O1, -O2, or -O3] (default = '-O0')")
+	addBuildFlags(cmd)
 
 	return cmd
 }
 
-func runCmd(_ *cobra.Command, _ []string) {
+func runCmd(c *cobra.Command, _ []string) {
 	// Build
-	output := buildProject()
+	project, output := buildProject(c, getArtifact)
+
+	if project.Config.Output != workspace.ExecutableOutput {
+		log.Fatalf("Only executables can be run but the project is a '%s'.\n", project.Config.Output)
+	}
 
 	// Run
 	cmd := exec.Command(output)
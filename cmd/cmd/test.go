@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"errors"
+	"fireball/core/ast"
+	"fireball/core/llvm"
+	"fireball/core/scanner"
+	"fireball/core/types"
+	"fireball/core/workspace"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func GetTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test [pattern]",
+		Short: "Build and run the tests of the project.",
+		Long:  "Build and run the functions marked with #[Test]. A pattern only runs the tests whose name contains it, or matches it if it contains '*', '?' or '['.",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   testCmd,
+	}
+
+	addBuildFlags(cmd)
+
+	return cmd
+}
+
+func testCmd(cmd *cobra.Command, args []string) {
+	pattern := ""
+	if len(args) > 0 {
+		pattern = args[0]
+	}
+
+	// Build
+	_, output := buildProject(cmd, func(project *workspace.Project) artifact {
+		return getTestArtifact(project, pattern)
+	})
+
+	// Run, the runner exits with a non-zero code if a test failed
+	run := exec.Command(output)
+
+	run.Stdin = os.Stdin
+	run.Stdout = os.Stdout
+	run.Stderr = os.Stderr
+
+	if err := run.Run(); err != nil {
+		var exitErr *exec.ExitError
+
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			os.Exit(exitErr.ExitCode())
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(4)
+	}
+}
+
+// getTestArtifact returns an executable running the tests matching the pattern, it replaces the entrypoint calling
+// main so libraries can be tested too.
+func getTestArtifact(project *workspace.Project, pattern string) artifact {
+	tests := getTests(project, pattern, &types.TestAttribute{})
+	text := strings.Builder{}
+
+	for _, test := range tests {
+		text.WriteString(test.name + " " + test.function.MangledName() + "\n")
+	}
+
+	return artifact{
+		output:         workspace.ExecutableOutput,
+		name:           project.Config.Name + "-tests",
+		entrypointName: "__tests",
+		entrypointText: text.String(),
+		emitEntrypoint: func(path string) error {
+			return generateTestRunner(project, tests, path)
+		},
+	}
+}
+
+// test is a function marked with the Test or Bench attribute.
+type test struct {
+	// Function name prefixed with the path of its file relative to the source folder, like 'math/vec.fb::add'
+	name string
+
+	function *ast.Func
+}
+
+// getTests returns the functions of the project with the attribute matching the pattern, sorted by file and
+// declaration order.
+func getTests(project *workspace.Project, pattern string, attribute any) []test {
+	var tests []test
+
+	for _, file := range project.SortedFiles() {
+		path, err := filepath.Rel(project.Config.Src, file.Path)
+		if err != nil {
+			path = file.Path
+		}
+
+		for _, decl := range file.Decls {
+			function, ok := decl.(*ast.Func)
+			if !ok {
+				continue
+			}
+
+			if !function.GetAttribute(attribute) {
+				continue
+			}
+
+			name := filepath.ToSlash(path) + "::" + function.Name.Lexeme
+
+			if matchesPattern(name, function.Name.Lexeme, pattern) {
+				tests = append(tests, test{
+					name:     name,
+					function: function,
+				})
+			}
+		}
+	}
+
+	return tests
+}
+
+// matchesPattern returns true if the full or function name matches a glob pattern, patterns without wildcards only
+// need to be contained in the full name.
+func matchesPattern(name, function, pattern string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	if !strings.ContainsAny(pattern, "*?[") {
+		return strings.Contains(name, pattern)
+	}
+
+	for _, candidate := range []string{name, function} {
+		if matched, _ := filepath.Match(pattern, candidate); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// monotonicClock returns the value of CLOCK_MONOTONIC for clock_gettime on the target.
+func monotonicClock(project *workspace.Project) int64 {
+	if project.Target.OS == "darwin" {
+		return 6
+	}
+
+	return 1
+}
+
+// generateTestRunner generates a main function running every test in a forked child process, so crashing tests do
+// not take down the runner. A test passes if its process exits normally with a zero status.
+func generateTestRunner(project *workspace.Project, tests []test, path string) error {
+	// Create module
+	m := llvm.NewModule(project.Target)
+	m.Source("__tests")
+
+	if !project.Profile.DebugInfo {
+		m.StripDebugInfo()
+	}
+
+	void := m.Void()
+	i32 := m.Primitive("i32", 32, llvm.SignedEncoding)
+	i64 := m.Primitive("i64", 64, llvm.SignedEncoding)
+	f64 := m.Primitive("f64", 64, llvm.FloatEncoding)
+	long := m.Primitive("long", project.Target.PointerSize()*8, llvm.SignedEncoding)
+	ptr := m.Pointer("*u8", m.Primitive("u8", 8, llvm.UnsignedEncoding))
+	longPtr := m.Pointer("*long", long)
+	timespec := m.Array("timespec", 2, long)
+
+	fflush := m.Declare(m.Function("fflush", []llvm.Type{ptr}, false, i32))
+	printf := m.Declare(m.Function("printf", []llvm.Type{ptr}, true, i32))
+	fork := m.Declare(m.Function("fork", []llvm.Type{}, false, i32))
+	waitpid := m.Declare(m.Function("waitpid", []llvm.Type{i32, ptr, i32}, false, i32))
+	exit := m.Declare(m.Function("exit", []llvm.Type{i32}, false, void))
+	clockGettime := m.Declare(m.Function("clock_gettime", []llvm.Type{i32, ptr}, false, i32))
+
+	clock := monotonicClock(project)
+
+	// Main
+	main := m.Define(m.Function("main", []llvm.Type{}, false, i32), "_fireball_tests")
+	main.PushScope()
+
+	block := main.Block("")
+
+	call := func(function llvm.Value, args []llvm.Value, returns llvm.Type) llvm.Value {
+		value := block.Call(function, args, returns)
+		value.SetLocation(scanner.Token{})
+
+		return value
+	}
+
+	literal := func(type_ llvm.Type, value int64) llvm.Value {
+		return main.Literal(type_, llvm.Literal{Signed: value})
+	}
+
+	// Timespec fields are C longs, the math is done in 64 bits so it does not overflow on 32-bit targets
+	field := func(time llvm.Value, index int64) llvm.Value {
+		value := block.Load(block.GetElementPtr(time, []llvm.Value{literal(i32, 0), literal(i32, index)}, longPtr, timespec))
+
+		if project.Target.PointerSize() < 8 {
+			return block.Cast(llvm.SExt, value, i64)
+		}
+
+		return value
+	}
+
+	// elapsed returns the milliseconds between the two timespecs
+	elapsed := func(start, end llvm.Value) llvm.Value {
+		seconds := block.Binary(llvm.Sub, field(end, 0), field(start, 0))
+		nanoseconds := block.Binary(llvm.Sub, field(end, 1), field(start, 1))
+
+		total := block.Binary(llvm.Add, block.Binary(llvm.Mul, seconds, literal(i64, 1_000_000_000)), nanoseconds)
+		return block.Binary(llvm.Mul, block.Cast(llvm.SiToFp, total, f64), main.Literal(f64, llvm.Literal{Floating: 1e-6}))
+	}
+
+	increment := func(counter llvm.Value) {
+		block.Store(counter, block.Binary(llvm.Add, block.Load(counter), literal(i32, 1)))
+	}
+
+	// Locals
+	passed := block.Alloca(i32)
+	failed := block.Alloca(i32)
+	status := block.Alloca(i32)
+	start := block.Alloca(timespec)
+	end := block.Alloca(timespec)
+
+	block.Store(passed, literal(i32, 0))
+	block.Store(failed, literal(i32, 0))
+
+	call(printf, []llvm.Value{m.Constant("running %d tests\n\n"), literal(i32, int64(len(tests)))}, i32)
+
+	// Tests
+	for _, test := range tests {
+		function := m.Declare(m.Function(test.function.MangledName(), []llvm.Type{}, false, void))
+		name := m.Constant(test.name)
+
+		child := main.Block("test.child")
+		parent := main.Block("test.parent")
+		pass := main.Block("test.pass")
+		fail := main.Block("test.fail")
+		next := main.Block("test.next")
+
+		// Buffered output would be printed by both processes
+		call(fflush, []llvm.Value{main.LiteralRaw(ptr, "null")}, i32)
+		call(clockGettime, []llvm.Value{literal(i32, clock), start}, i32)
+
+		pid := call(fork, []llvm.Value{}, i32)
+		block.Br(block.Binary(llvm.Eq, pid, literal(i32, 0)), child, parent)
+
+		// Child, panics abort the process
+		block = child
+		call(function, []llvm.Value{}, void)
+		call(exit, []llvm.Value{literal(i32, 0)}, void)
+		block.Unreachable()
+
+		// Parent, a failed fork or wait leaves the status non-zero
+		block = parent
+		block.Store(status, literal(i32, 1))
+		call(waitpid, []llvm.Value{pid, status, literal(i32, 0)}, i32)
+		call(clockGettime, []llvm.Value{literal(i32, clock), end}, i32)
+
+		ms := elapsed(start, end)
+		block.Br(block.Binary(llvm.Eq, block.Load(status), literal(i32, 0)), pass, fail)
+
+		// Pass
+		block = pass
+		call(printf, []llvm.Value{m.Constant("PASS  %s (%.2f ms)\n"), name, ms}, i32)
+		increment(passed)
+		block.Br(nil, next, nil)
+
+		// Fail
+		block = fail
+		call(printf, []llvm.Value{m.Constant("FAIL  %s (%.2f ms)\n"), name, ms}, i32)
+		increment(failed)
+		block.Br(nil, next, nil)
+
+		block = next
+	}
+
+	// Summary
+	failedCount := block.Load(failed)
+	passedCount := block.Load(passed)
+
+	call(printf, []llvm.Value{m.Constant("\n%d passed, %d failed\n"), passedCount, failedCount}, i32)
+
+	block.Ret(block.Cast(llvm.ZExt, block.Binary(llvm.Ne, failedCount, literal(i32, 0)), i32))
+	main.PopScope()
+
+	// Write module
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	llvm.WriteText(m, file)
+
+	_ = file.Close()
+	return nil
+}
@@ -39,6 +39,11 @@ func (a *annotator) VisitStruct(decl *ast.Struct) {
 }
 
 func (a *annotator) VisitImpl(decl *ast.Impl) {
+	// Generated code has no source to annotate
+	if decl.Derived {
+		return
+	}
+
 	decl.AcceptChildren(a)
 }
 
@@ -60,6 +65,10 @@ func (a *annotator) VisitFunc(decl *ast.Func) {
 	decl.AcceptChildren(a)
 }
 
+func (a *annotator) VisitStaticAssert(decl *ast.StaticAssert) {
+	decl.AcceptChildren(a)
+}
+
 // Statements
 
 func (a *annotator) VisitBlock(stmt *ast.Block) {
@@ -78,6 +87,10 @@ func (a *annotator) VisitVariable(stmt *ast.Variable) {
 	stmt.AcceptChildren(a)
 }
 
+func (a *annotator) VisitDestructure(stmt *ast.Destructure) {
+	stmt.AcceptChildren(a)
+}
+
 func (a *annotator) VisitIf(stmt *ast.If) {
 	stmt.AcceptChildren(a)
 }
@@ -98,6 +111,10 @@ func (a *annotator) VisitContinue(stmt *ast.Continue) {
 	stmt.AcceptChildren(a)
 }
 
+func (a *annotator) VisitStaticAssertStmt(stmt *ast.StaticAssertStmt) {
+	stmt.AcceptChildren(a)
+}
+
 // Expressions
 
 func (a *annotator) VisitGroup(expr *ast.Group) {
@@ -132,6 +149,14 @@ func (a *annotator) VisitLogical(expr *ast.Logical) {
 	expr.AcceptChildren(a)
 }
 
+func (a *annotator) VisitIfExpr(expr *ast.IfExpr) {
+	expr.AcceptChildren(a)
+}
+
+func (a *annotator) VisitBlockExpr(expr *ast.BlockExpr) {
+	expr.AcceptChildren(a)
+}
+
 func (a *annotator) VisitIdentifier(expr *ast.Identifier) {
 	expr.AcceptChildren(a)
 }
@@ -148,6 +173,10 @@ func (a *annotator) VisitTypeCall(expr *ast.TypeCall) {
 	expr.AcceptChildren(a)
 }
 
+func (a *annotator) VisitAssert(expr *ast.Assert) {
+	expr.AcceptChildren(a)
+}
+
 func (a *annotator) VisitCall(expr *ast.Call) {
 	if false {
 		if expr.Callee.Result().Kind == ast.FunctionResultKind {
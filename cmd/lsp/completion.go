@@ -0,0 +1,62 @@
+package lsp
+
+import (
+	"cmp"
+	"fireball/core/ast"
+	"fireball/core/workspace"
+	"github.com/MineGame159/protocol"
+	"slices"
+)
+
+func getCompletions(current *workspace.File) *protocol.CompletionList {
+	items := make([]protocol.CompletionItem, 0, 64)
+
+	for _, file := range current.Project.Files {
+		// Private declarations are only visible in their own file
+		local := file == current
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.Struct:
+				if local || decl.Pub {
+					items = append(items, newCompletion(decl.Name.Lexeme, protocol.CompletionItemKindStruct, "struct "+decl.Name.Lexeme, decl.Docs))
+				}
+
+			case *ast.Enum:
+				if local || decl.Pub {
+					items = append(items, newCompletion(decl.Name.Lexeme, protocol.CompletionItemKindEnum, "enum "+decl.Name.Lexeme, decl.Docs))
+				}
+
+			case *ast.Func:
+				if local || decl.Pub {
+					items = append(items, newCompletion(decl.Name.Lexeme, protocol.CompletionItemKindFunction, decl.Signature(true), decl.Docs))
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(items, func(a, b protocol.CompletionItem) int {
+		return cmp.Compare(a.Label, b.Label)
+	})
+
+	return &protocol.CompletionList{
+		Items: items,
+	}
+}
+
+func newCompletion(label string, kind protocol.CompletionItemKind, detail, docs string) protocol.CompletionItem {
+	item := protocol.CompletionItem{
+		Label:  label,
+		Kind:   kind,
+		Detail: detail,
+	}
+
+	if docs != "" {
+		item.Documentation = protocol.MarkupContent{
+			Kind:  protocol.Markdown,
+			Value: docs,
+		}
+	}
+
+	return item
+}
@@ -101,6 +101,8 @@ func (h *handler) Initialize(_ context.Context, params *protocol.InitializeParam
 						protocol.SemanticTokenEnum,
 						protocol.SemanticTokenProperty,
 						protocol.SemanticTokenEnumMember,
+						protocol.SemanticTokenNumber,
+						protocol.SemanticTokenString,
 					},
 					TokenModifiers: []protocol.SemanticTokenModifiers{},
 				},
@@ -109,6 +111,7 @@ func (h *handler) Initialize(_ context.Context, params *protocol.InitializeParam
 			DocumentSymbolProvider: &protocol.DocumentSymbolOptions{
 				Label: "Fireball",
 			},
+			CompletionProvider:      &protocol.CompletionOptions{},
 			HoverProvider:           true,
 			InlayHintProvider:       true,
 			WorkspaceSymbolProvider: true,
@@ -310,7 +313,7 @@ func (h *handler) SemanticTokensFull(_ context.Context, params *protocol.Semanti
 	}
 
 	// Get semantic tokens
-	data := highlight(file.Decls)
+	data := highlight(file.Decls, file.Inactive)
 
 	tokens := &protocol.SemanticTokens{
 		Data: data,
@@ -381,7 +384,22 @@ func (h *handler) Hover(_ context.Context, params *protocol.HoverParams) (result
 	}
 
 	// Get hover
-	return getHover(file.Decls, pos), nil
+	return getHover(file.Project, file.Decls, pos), nil
+}
+
+func (h *handler) Completion(_ context.Context, params *protocol.CompletionParams) (result *protocol.CompletionList, err error) {
+	defer stop(start(h, "Completion"))
+
+	// Get document
+	file := h.getFile(params.TextDocument.URI)
+	if file == nil {
+		return nil, nil
+	}
+
+	file.EnsureChecked()
+
+	// Get completions
+	return getCompletions(file), nil
 }
 
 func (h *handler) Symbols(_ context.Context, _ *protocol.WorkspaceSymbolParams) (result []protocol.SymbolInformation, err error) {
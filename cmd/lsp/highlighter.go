@@ -7,7 +7,9 @@ import (
 	"fireball/core/scanner"
 	"fireball/core/types"
 	"fireball/core/utils"
+	"math"
 	"slices"
+	"strings"
 )
 
 type highlighter struct {
@@ -16,10 +18,12 @@ type highlighter struct {
 	functions utils.Set[string]
 	params    []ast.Param
 
+	inactive bool
+
 	tokens []semantic
 }
 
-func highlight(decls []ast.Decl) []uint32 {
+func highlight(decls []ast.Decl, inactive []ast.Decl) []uint32 {
 	h := &highlighter{
 		enums:     utils.NewSet[string](),
 		functions: utils.NewSet[string](),
@@ -38,6 +42,13 @@ func highlight(decls []ast.Decl) []uint32 {
 		h.AcceptDecl(decl)
 	}
 
+	// Inactive declarations are not checked so only the declared names are highlighted
+	h.inactive = true
+
+	for _, decl := range inactive {
+		h.AcceptDecl(decl)
+	}
+
 	return h.data()
 }
 
@@ -54,6 +65,11 @@ func (h *highlighter) VisitStruct(decl *ast.Struct) {
 }
 
 func (h *highlighter) VisitImpl(decl *ast.Impl) {
+	// Generated code has no source to highlight
+	if decl.Derived {
+		return
+	}
+
 	h.addToken(decl.Struct, classKind)
 
 	decl.AcceptChildren(h)
@@ -81,6 +97,10 @@ func (h *highlighter) VisitFunc(decl *ast.Func) {
 	h.params = nil
 }
 
+func (h *highlighter) VisitStaticAssert(decl *ast.StaticAssert) {
+	decl.AcceptChildren(h)
+}
+
 // Statements
 
 func (h *highlighter) VisitBlock(stmt *ast.Block) {
@@ -97,6 +117,10 @@ func (h *highlighter) VisitVariable(stmt *ast.Variable) {
 	stmt.AcceptChildren(h)
 }
 
+func (h *highlighter) VisitDestructure(stmt *ast.Destructure) {
+	stmt.AcceptChildren(h)
+}
+
 func (h *highlighter) VisitIf(stmt *ast.If) {
 	stmt.AcceptChildren(h)
 }
@@ -117,6 +141,10 @@ func (h *highlighter) VisitContinue(stmt *ast.Continue) {
 	stmt.AcceptChildren(h)
 }
 
+func (h *highlighter) VisitStaticAssertStmt(stmt *ast.StaticAssertStmt) {
+	stmt.AcceptChildren(h)
+}
+
 // Expressions
 
 func (h *highlighter) VisitGroup(expr *ast.Group) {
@@ -124,6 +152,14 @@ func (h *highlighter) VisitGroup(expr *ast.Group) {
 }
 
 func (h *highlighter) VisitLiteral(expr *ast.Literal) {
+	switch expr.Value.Kind {
+	case scanner.Number, scanner.Hex, scanner.Binary, scanner.Octal:
+		h.addToken(expr.Value, numberKind)
+
+	case scanner.String, scanner.Character:
+		h.addToken(expr.Value, stringKind)
+	}
+
 	expr.AcceptChildren(h)
 }
 
@@ -151,7 +187,19 @@ func (h *highlighter) VisitLogical(expr *ast.Logical) {
 	expr.AcceptChildren(h)
 }
 
+func (h *highlighter) VisitIfExpr(expr *ast.IfExpr) {
+	expr.AcceptChildren(h)
+}
+
+func (h *highlighter) VisitBlockExpr(expr *ast.BlockExpr) {
+	expr.AcceptChildren(h)
+}
+
 func (h *highlighter) VisitIdentifier(expr *ast.Identifier) {
+	if h.inactive {
+		return
+	}
+
 	var kind semanticKind
 
 	switch expr.Kind {
@@ -190,6 +238,16 @@ func (h *highlighter) VisitTypeCall(expr *ast.TypeCall) {
 	expr.AcceptChildren(h)
 }
 
+func (h *highlighter) VisitAssert(expr *ast.Assert) {
+	h.addToken(expr.Token(), functionKind)
+
+	if expr.Message.Kind == scanner.String {
+		h.addToken(expr.Message, stringKind)
+	}
+
+	expr.AcceptChildren(h)
+}
+
 func (h *highlighter) VisitCall(expr *ast.Call) {
 	expr.AcceptChildren(h)
 }
@@ -199,6 +257,11 @@ func (h *highlighter) VisitIndex(expr *ast.Index) {
 }
 
 func (h *highlighter) VisitMember(expr *ast.Member) {
+	if h.inactive || expr.Implicit {
+		expr.AcceptChildren(h)
+		return
+	}
+
 	if expr.Result().Kind == ast.FunctionResultKind {
 		h.addToken(expr.Name, functionKind)
 	} else if i, ok := expr.Value.(*ast.Identifier); ok && i.Kind == ast.EnumKind {
@@ -256,38 +319,48 @@ const (
 	enumKind
 	propertyKind
 	enumMemberKind
+	numberKind
+	stringKind
 )
 
 type semantic struct {
 	line   uint16
-	column uint8
-
-	// length - 0b00011111
-	// kind   - 0b11100000
-	lengthKind uint8
+	column uint16
+	length uint16
+	kind   semanticKind
 }
 
 func newSemantic(line, column, length int, kind semanticKind) semantic {
-	length = min(length, 31)
-
 	return semantic{
-		line:       uint16(line - 1),
-		column:     uint8(column),
-		lengthKind: (uint8(length) & 0b00011111) | ((uint8(kind) << 5) & 0b11100000),
+		line:   uint16(line - 1),
+		column: uint16(column),
+		length: uint16(min(length, math.MaxUint16)),
+		kind:   kind,
 	}
 }
 
 func (h *highlighter) addToken(token scanner.Token, kind semanticKind) {
-	if token.Column() < 256 {
-		h.tokens = append(h.tokens, newSemantic(token.Line(), token.Column(), len(token.Lexeme), kind))
+	// Multi-line tokens, like raw and multi-line strings, are split into one semantic token per line
+	line := token.Line()
+	column := token.Column()
+
+	for i, part := range strings.Split(token.Lexeme, "\n") {
+		if i > 0 {
+			line++
+			column = 0
+		}
+
+		part = strings.TrimSuffix(part, "\r")
+
+		if part != "" {
+			h.tokens = append(h.tokens, newSemantic(line, column, len(part), kind))
+		}
 	}
 }
 
 func (h *highlighter) addRange(range_ core.Range, kind semanticKind) {
 	if range_.Start.Line == range_.End.Line {
-		if range_.Start.Column < 256 {
-			h.tokens = append(h.tokens, newSemantic(range_.Start.Line, range_.Start.Column, range_.End.Column-range_.Start.Column, kind))
-		}
+		h.tokens = append(h.tokens, newSemantic(range_.Start.Line, range_.Start.Column, range_.End.Column-range_.Start.Column, kind))
 	}
 }
 
@@ -309,7 +382,7 @@ func (h *highlighter) data() []uint32 {
 	data := make([]uint32, len(h.tokens)*5)
 
 	lastLine := uint16(0)
-	lastColumn := uint8(0)
+	lastColumn := uint16(0)
 
 	for i, token := range h.tokens {
 		if lastLine != token.line {
@@ -320,8 +393,8 @@ func (h *highlighter) data() []uint32 {
 
 		data[j+0] = uint32(token.line - lastLine)
 		data[j+1] = uint32(token.column - lastColumn)
-		data[j+2] = uint32(token.lengthKind & 0b00011111)
-		data[j+3] = uint32((token.lengthKind & 0b11100000) >> 5)
+		data[j+2] = uint32(token.length)
+		data[j+3] = uint32(token.kind)
 		data[j+4] = uint32(0)
 
 		lastLine = token.line
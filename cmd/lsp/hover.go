@@ -3,12 +3,20 @@ package lsp
 import (
 	"fireball/core"
 	"fireball/core/ast"
+	"fireball/core/types"
+	"fireball/core/utils"
+	"fmt"
 	"github.com/MineGame159/protocol"
 	"strconv"
 	"strings"
 )
 
-func getHover(decls []ast.Decl, pos core.Pos) *protocol.Hover {
+func getHover(resolver utils.Resolver, decls []ast.Decl, pos core.Pos) *protocol.Hover {
+	// Attributes are not part of the declaration ranges
+	if hover := getAttributeHover(resolver, decls, pos); hover != nil {
+		return hover
+	}
+
 	for _, decl := range decls {
 		// Get node under cursor
 		node := ast.GetLeaf(decl, pos)
@@ -67,13 +75,7 @@ func getHover(decls []ast.Decl, pos core.Pos) *protocol.Hover {
 						case_ := e.GetCase(m.Name.Lexeme)
 
 						if case_ != nil {
-							return &protocol.Hover{
-								Contents: protocol.MarkupContent{
-									Kind:  protocol.PlainText,
-									Value: strconv.Itoa(case_.Value),
-								},
-								Range: convertRangePtr(expr.Range()),
-							}
+							return newHover(strconv.Itoa(case_.Value), case_.Docs, expr.Range())
 						}
 					}
 				}
@@ -82,9 +84,9 @@ func getHover(decls []ast.Decl, pos core.Pos) *protocol.Hover {
 				value := 0
 
 				if t.Name.Lexeme == "sizeof" {
-					value = t.Target.Size()
+					value = t.Target.Size(resolver.GetTarget())
 				} else {
-					value = t.Target.Align()
+					value = t.Target.Align(resolver.GetTarget())
 				}
 
 				return &protocol.Hover{
@@ -99,6 +101,10 @@ func getHover(decls []ast.Decl, pos core.Pos) *protocol.Hover {
 			// ast.Expr
 			text := expr.Result().Type.String()
 
+			if i, ok := expr.(*ast.Identifier); ok && i.Kind == ast.VariableKind {
+				text = getVariableText(i.Identifier.Lexeme, expr.Result().Type, expr.Result().IsImmutable())
+			}
+
 			// Ignore literal expressions
 			if _, ok := expr.(*ast.Literal); ok {
 				text = ""
@@ -106,41 +112,210 @@ func getHover(decls []ast.Decl, pos core.Pos) *protocol.Hover {
 
 			// Return
 			if text != "" {
-				return &protocol.Hover{
-					Contents: protocol.MarkupContent{
-						Kind:  protocol.PlainText,
-						Value: text,
-					},
-					Range: convertRangePtr(expr.Range()),
-				}
+				return newHover(text, getExprDocs(expr), expr.Range())
 			}
 		} else if variable, ok := node.(*ast.Variable); ok {
 			// ast.Variable
 			return &protocol.Hover{
 				Contents: protocol.MarkupContent{
 					Kind:  protocol.PlainText,
-					Value: variable.Type.String(),
+					Value: getVariableText(variable.Name.Lexeme, variable.Type, variable.Immutable),
 				},
 				Range: convertRangePtr(core.TokenToRange(variable.Name)),
 			}
 		} else if enum, ok := node.(*ast.Enum); ok {
 			// ast.Enum
+			if range_ := core.TokenToRange(enum.Name); range_.Contains(pos) {
+				return newHover(getPubText(enum.Pub)+"enum "+enum.Name.Lexeme+" "+enum.Type.String(), enum.Docs, range_)
+			}
 
 			for _, case_ := range enum.Cases {
 				range_ := core.TokenToRange(case_.Name)
 
 				if range_.Contains(pos) {
-					return &protocol.Hover{
-						Contents: protocol.MarkupContent{
-							Kind:  protocol.PlainText,
-							Value: strconv.Itoa(case_.Value),
-						},
-						Range: convertRangePtr(range_),
+					return newHover(strconv.Itoa(case_.Value), case_.Docs, range_)
+				}
+			}
+		} else if struct_, ok := node.(*ast.Struct); ok {
+			// ast.Struct
+			if range_ := core.TokenToRange(struct_.Name); range_.Contains(pos) {
+				return newHover(getPubText(struct_.Pub)+"struct "+struct_.Name.Lexeme, struct_.Docs, range_)
+			}
+
+			for _, fields := range [][]ast.Field{struct_.StaticFields, struct_.Fields} {
+				for _, field := range fields {
+					range_ := core.TokenToRange(field.Name)
+
+					if range_.Contains(pos) {
+						return newHover(getPubText(field.Pub)+field.Type.String(), field.Docs, range_)
 					}
 				}
 			}
+		} else if function, ok := node.(*ast.Func); ok {
+			// ast.Func
+			if range_ := core.TokenToRange(function.Name); range_.Contains(pos) {
+				return newHover(getPubText(function.Pub)+"func "+function.Name.Lexeme+function.Signature(true), function.Docs, range_)
+			}
 		}
 	}
 
 	return nil
 }
+
+// getPubText returns the 'pub' qualifier of public declarations.
+func getPubText(pub bool) string {
+	if pub {
+		return "pub "
+	}
+
+	return ""
+}
+
+// getVariableText returns the hover text of a variable, including its 'var' or 'let' qualifier.
+func getVariableText(name string, type_ types.Type, immutable bool) string {
+	if immutable {
+		return "let " + name + " " + type_.String()
+	}
+
+	return "var " + name + " " + type_.String()
+}
+
+func newHover(text, docs string, range_ core.Range) *protocol.Hover {
+	content := protocol.MarkupContent{
+		Kind:  protocol.PlainText,
+		Value: text,
+	}
+
+	if docs != "" {
+		content = protocol.MarkupContent{
+			Kind:  protocol.Markdown,
+			Value: fmt.Sprintf("```fireball\n%s\n```\n%s", text, docs),
+		}
+	}
+
+	return &protocol.Hover{
+		Contents: content,
+		Range:    convertRangePtr(range_),
+	}
+}
+
+// getExprDocs returns the doc comment of the declaration an expression refers to.
+func getExprDocs(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Identifier:
+		if expr.Kind == ast.FunctionKind || expr.Kind == ast.StructKind || expr.Kind == ast.EnumKind {
+			return getTypeDocs(expr.Result().Type)
+		}
+
+	case *ast.Member:
+		if expr.Result().Kind == ast.FunctionResultKind {
+			return getTypeDocs(expr.Result().Type)
+		}
+
+		type_ := expr.Value.Result().Type
+		if pointer, ok := type_.(*types.PointerType); ok {
+			type_ = pointer.Pointee
+		}
+
+		if struct_, ok := type_.(*ast.Struct); ok {
+			var field *ast.Field
+
+			if expr.Value.Result().Kind == ast.TypeResultKind {
+				_, field = struct_.GetStaticField(expr.Name.Lexeme)
+			} else {
+				_, field = struct_.GetField(expr.Name.Lexeme)
+			}
+
+			if field != nil {
+				return field.Docs
+			}
+		}
+	}
+
+	return ""
+}
+
+func getTypeDocs(type_ types.Type) string {
+	switch type_ := type_.(type) {
+	case *ast.Struct:
+		return type_.Docs
+	case *ast.Enum:
+		return type_.Docs
+	case *ast.Func:
+		return type_.Docs
+
+	default:
+		return ""
+	}
+}
+
+func getAttributeHover(resolver utils.Resolver, decls []ast.Decl, pos core.Pos) *protocol.Hover {
+	attributes := make([]ast.Attribute, 0, 16)
+
+	for _, decl := range decls {
+		if impl, ok := decl.(*ast.Impl); ok {
+			if impl.Derived {
+				continue
+			}
+
+			attributes = append(attributes, impl.Attributes...)
+
+			for _, function := range impl.Functions {
+				attributes = appendDeclAttributes(attributes, function)
+			}
+		} else {
+			attributes = appendDeclAttributes(attributes, decl)
+		}
+	}
+
+	for _, attribute := range attributes {
+		range_ := core.TokenToRange(attribute.Name)
+
+		if range_.Contains(pos) {
+			schema := types.GetBuiltinAttribute(attribute.Name.Lexeme)
+			if schema == nil {
+				schema = resolver.GetAttribute(attribute.Name.Lexeme)
+			}
+
+			if schema != nil {
+				text := schema.Signature()
+
+				if schema.Description != "" {
+					text += "\n\n" + schema.Description
+				}
+
+				return &protocol.Hover{
+					Contents: protocol.MarkupContent{
+						Kind:  protocol.PlainText,
+						Value: text,
+					},
+					Range: convertRangePtr(range_),
+				}
+			}
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func appendDeclAttributes(attributes []ast.Attribute, decl ast.Decl) []ast.Attribute {
+	attributes = append(attributes, decl.(ast.Attributed).GetAttributes()...)
+
+	if struct_, ok := decl.(*ast.Struct); ok {
+		for _, field := range struct_.StaticFields {
+			attributes = append(attributes, field.Attributes...)
+		}
+
+		for _, field := range struct_.Fields {
+			attributes = append(attributes, field.Attributes...)
+		}
+	} else if function, ok := decl.(*ast.Func); ok {
+		for _, param := range function.Params {
+			attributes = append(attributes, param.Attributes...)
+		}
+	}
+
+	return attributes
+}
@@ -8,6 +8,7 @@ import (
 	"go.lsp.dev/uri"
 	"path/filepath"
 	"strconv"
+	"strings"
 )
 
 type symbol struct {
@@ -35,7 +36,11 @@ func getSymbols(symbols symbolConsumer, files []*workspace.File) {
 	for _, file := range files {
 		for _, decl := range file.Decls {
 			if impl, ok := decl.(*ast.Impl); ok && impl.Type_ != nil {
-				methodCount[impl.Type_] += len(impl.Functions)
+				for _, function := range impl.Functions {
+					if isSymbol(impl, function.(*ast.Func)) {
+						methodCount[impl.Type_]++
+					}
+				}
 			}
 		}
 	}
@@ -49,6 +54,7 @@ func getSymbols(symbols symbolConsumer, files []*workspace.File) {
 				id := symbols.add(symbol{
 					kind:           protocol.SymbolKindStruct,
 					name:           struct_.Name.Lexeme,
+					detail:         getSymbolDetail("", struct_.Docs),
 					range_:         struct_.Range(),
 					selectionRange: core.TokenToRange(struct_.Name),
 					file:           file,
@@ -61,7 +67,7 @@ func getSymbols(symbols symbolConsumer, files []*workspace.File) {
 						file:           file,
 						kind:           protocol.SymbolKindField,
 						name:           field.Name.Lexeme,
-						detail:         field.Type.String(),
+						detail:         getSymbolDetail(field.Type.String(), field.Docs),
 						range_:         range_,
 						selectionRange: range_,
 					})
@@ -74,7 +80,7 @@ func getSymbols(symbols symbolConsumer, files []*workspace.File) {
 						file:           file,
 						kind:           protocol.SymbolKindField,
 						name:           field.Name.Lexeme,
-						detail:         field.Type.String(),
+						detail:         getSymbolDetail(field.Type.String(), field.Docs),
 						range_:         range_,
 						selectionRange: range_,
 					})
@@ -105,8 +111,12 @@ func getSymbols(symbols symbolConsumer, files []*workspace.File) {
 					function := f.(*ast.Func)
 					detail := ""
 
+					if !isSymbol(impl, function) {
+						continue
+					}
+
 					if symbols.supportsDetail() {
-						detail = function.Signature(true)
+						detail = getSymbolDetail(function.Signature(true), function.Docs)
 					}
 
 					symbols.addChild(id, symbol{
@@ -123,6 +133,7 @@ func getSymbols(symbols symbolConsumer, files []*workspace.File) {
 				id := symbols.add(symbol{
 					kind:           protocol.SymbolKindEnum,
 					name:           enum.Name.Lexeme,
+					detail:         getSymbolDetail("", enum.Docs),
 					range_:         enum.Range(),
 					selectionRange: core.TokenToRange(enum.Name),
 					file:           file,
@@ -135,7 +146,7 @@ func getSymbols(symbols symbolConsumer, files []*workspace.File) {
 						file:           file,
 						kind:           protocol.SymbolKindEnumMember,
 						name:           case_.Name.Lexeme,
-						detail:         strconv.Itoa(case_.Value),
+						detail:         getSymbolDetail(strconv.Itoa(case_.Value), case_.Docs),
 						range_:         range_,
 						selectionRange: range_,
 					})
@@ -145,7 +156,7 @@ func getSymbols(symbols symbolConsumer, files []*workspace.File) {
 				detail := ""
 
 				if symbols.supportsDetail() {
-					detail = function.Signature(true)
+					detail = getSymbolDetail(function.Signature(true), function.Docs)
 				}
 
 				symbols.add(symbol{
@@ -161,6 +172,20 @@ func getSymbols(symbols symbolConsumer, files []*workspace.File) {
 	}
 }
 
+// getSymbolDetail appends the first line of the doc comment to the detail.
+func getSymbolDetail(detail, docs string) string {
+	summary, _, _ := strings.Cut(docs, "\n")
+
+	if summary == "" {
+		return detail
+	}
+	if detail == "" {
+		return summary
+	}
+
+	return detail + " - " + summary
+}
+
 // Document symbols
 
 type documentSymbolConsumer struct {
@@ -238,3 +263,8 @@ func (w *workspaceSymbolConsumer) convert(symbol symbol, parent int) protocol.Sy
 		ContainerName: containerName,
 	}
 }
+
+// isSymbol returns false for the private helper methods of derived impls.
+func isSymbol(impl *ast.Impl, function *ast.Func) bool {
+	return !impl.Derived || function.Pub
+}
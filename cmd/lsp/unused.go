@@ -41,11 +41,6 @@ func (h *handler) ColorPresentation(ctx context.Context, params *protocol.ColorP
 	return nil, errors.New("not implemented")
 }
 
-//goland:noinspection GoUnusedParameter
-func (h *handler) Completion(ctx context.Context, params *protocol.CompletionParams) (result *protocol.CompletionList, err error) {
-	return nil, errors.New("not implemented")
-}
-
 //goland:noinspection GoUnusedParameter
 func (h *handler) CompletionResolve(ctx context.Context, params *protocol.CompletionItem) (result *protocol.CompletionItem, err error) {
 	return nil, errors.New("not implemented")
@@ -18,6 +18,9 @@ func main() {
 		cmd.GetBuildCmd(),
 		cmd.GetRunCmd(),
 		cmd.GetInitCommand(),
+		cmd.GetDoctorCmd(),
+		cmd.GetTestCmd(),
+		cmd.GetBenchCmd(),
 		lsp.GetCmd(),
 	)
 
@@ -1,6 +1,7 @@
 package architecture
 
 import (
+	"fireball/core/target"
 	"fireball/core/types"
 )
 
@@ -10,15 +11,17 @@ type Layout interface {
 }
 
 type CLayout struct {
+	Target target.Target
+
 	biggestAlign int
 	offset       int
 }
 
 func (l *CLayout) Add(type_ types.Type) int {
-	l.biggestAlign = max(l.biggestAlign, type_.Align())
+	l.biggestAlign = max(l.biggestAlign, type_.Align(l.Target))
 
 	offset := align(l.offset, l.biggestAlign)
-	l.offset = offset + type_.Size()
+	l.offset = offset + type_.Size(l.Target)
 
 	return offset
 }
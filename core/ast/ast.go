@@ -134,6 +134,11 @@ func (v *get) Accept(node Node) {
 			v.node = node
 			return
 		}
+
+		if function, ok := node.(*Func); ok && core.TokenToRange(function.Name).Contains(v.pos) {
+			v.node = node
+			return
+		}
 	}
 }
 
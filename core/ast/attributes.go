@@ -0,0 +1,133 @@
+package ast
+
+import (
+	"fireball/core/scanner"
+	"fireball/core/types"
+	"reflect"
+)
+
+// Attribute is a single entry of an '#[...]' list, Value is set once it was validated against its schema.
+type Attribute struct {
+	Name scanner.Token
+	Args []scanner.Token
+
+	Value any
+}
+
+// Attributed is implemented by all nodes that can have attributes.
+type Attributed interface {
+	Node
+
+	GetAttributes() []Attribute
+	SetAttributes(attributes []Attribute)
+}
+
+// Resolve validates the arguments against the schema and creates the attribute value, errors are reported with the
+// provided function. The value is always created so invalid arguments are left out as if they were missing.
+func (a *Attribute) Resolve(schema *types.AttributeSchema, report func(token scanner.Token, format string, args ...any)) bool {
+	valid := true
+	variadic := len(schema.Params) > 0 && schema.Params[len(schema.Params)-1].Variadic
+
+	if !variadic && len(a.Args) > len(schema.Params) {
+		switch len(schema.Params) {
+		case 0:
+			report(a.Args[0], "Attribute '%s' doesn't have any parameters.", a.Name)
+		case 1:
+			report(a.Args[1], "Attribute '%s' only has 1 parameter.", a.Name)
+		default:
+			report(a.Args[len(schema.Params)], "Attribute '%s' only has %d parameters.", a.Name, len(schema.Params))
+		}
+
+		valid = false
+	}
+
+	args := make([]any, len(schema.Params))
+
+	for i, param := range schema.Params {
+		if param.Variadic {
+			values := make([]any, 0, len(a.Args))
+
+			for _, arg := range a.Args[min(i, len(a.Args)):] {
+				if value, ok := parseAttributeArg(arg, param.Kind); ok {
+					values = append(values, value)
+				} else {
+					report(arg, "Expected %s value for parameter '%s' but got '%s'.", param.Kind, param.Name, arg)
+					valid = false
+				}
+			}
+
+			if len(values) == 0 && !param.Optional {
+				report(a.Name, "Attribute '%s' is missing the '%s' parameter.", a.Name, param.Name)
+				valid = false
+			}
+
+			args[i] = values
+			continue
+		}
+
+		if i >= len(a.Args) {
+			if !param.Optional {
+				report(a.Name, "Attribute '%s' is missing the '%s' parameter.", a.Name, param.Name)
+				valid = false
+			}
+
+			continue
+		}
+
+		value, ok := parseAttributeArg(a.Args[i], param.Kind)
+
+		if !ok {
+			report(a.Args[i], "Expected %s value for parameter '%s' but got '%s'.", param.Kind, param.Name, a.Args[i])
+			valid = false
+
+			continue
+		}
+
+		args[i] = value
+	}
+
+	a.Value = schema.Create(args)
+	return valid
+}
+
+func parseAttributeArg(arg scanner.Token, kind types.AttributeParamKind) (any, bool) {
+	switch kind {
+	case types.StringParam:
+		if arg.Kind == scanner.String {
+			value, err := scanner.ParseString(arg)
+			return value, err == nil
+		}
+
+	case types.IntParam:
+		if arg.Kind == scanner.Number || arg.Kind == scanner.Hex || arg.Kind == scanner.Binary || arg.Kind == scanner.Octal {
+			return scanner.ParseInteger(arg)
+		}
+
+	case types.BoolParam:
+		if arg.Kind == scanner.True || arg.Kind == scanner.False {
+			return arg.Kind == scanner.True, true
+		}
+
+	case types.IdentifierParam:
+		if arg.Kind == scanner.Identifier {
+			return arg.Lexeme, true
+		}
+	}
+
+	return nil, false
+}
+
+// GetAttribute finds the first attribute value with the same type as the pointed to value and stores it there.
+func GetAttribute(node Attributed, attribute any) bool {
+	elem := reflect.ValueOf(attribute).Elem()
+	type_ := elem.Type()
+
+	for _, attr := range node.GetAttributes() {
+		if attr.Value != nil && reflect.TypeOf(attr.Value) == type_ {
+			elem.Set(reflect.ValueOf(attr.Value))
+			return true
+		}
+	}
+
+	return false
+}
@@ -12,6 +12,7 @@ type DeclVisitor interface {
 	VisitImpl(decl *Impl)
 	VisitEnum(decl *Enum)
 	VisitFunc(decl *Func)
+	VisitStaticAssert(decl *StaticAssert)
 }
 
 type Decl interface {
@@ -26,6 +27,10 @@ type Struct struct {
 	range_ core.Range
 	parent Node
 
+	Attributes   []Attribute
+	Docs         string
+	Pub          bool
+	Namespace    string
 	Name         scanner.Token
 	StaticFields []Field
 	Fields       []Field
@@ -113,15 +118,27 @@ func (s *Struct) String() string {
 	return s.Token().Lexeme
 }
 
+func (s *Struct) GetAttributes() []Attribute {
+	return s.Attributes
+}
+
+func (s *Struct) SetAttributes(attributes []Attribute) {
+	s.Attributes = attributes
+}
+
 func (s *Struct) SetChildrenParent() {
 }
 
 // Field
 
 type Field struct {
-	Parent *Struct
-	Name   scanner.Token
-	Type   types.Type
+	Docs       string
+	Attributes []Attribute
+	Parent     *Struct
+	Pub        bool
+	Embedded   bool
+	Name       scanner.Token
+	Type       types.Type
 }
 
 // Impl
@@ -130,9 +147,11 @@ type Impl struct {
 	range_ core.Range
 	parent Node
 
-	Struct    scanner.Token
-	Type_     *Struct
-	Functions []Decl
+	Attributes []Attribute
+	Struct     scanner.Token
+	Type_      *Struct
+	Functions  []Decl
+	Derived    bool
 }
 
 func (i *Impl) Token() scanner.Token {
@@ -201,6 +220,14 @@ func (i *Impl) String() string {
 	return i.Token().Lexeme
 }
 
+func (i *Impl) GetAttributes() []Attribute {
+	return i.Attributes
+}
+
+func (i *Impl) SetAttributes(attributes []Attribute) {
+	i.Attributes = attributes
+}
+
 func (i *Impl) SetChildrenParent() {
 	for i_ := range i.Functions {
 		if i.Functions[i_] != nil {
@@ -215,10 +242,14 @@ type Enum struct {
 	range_ core.Range
 	parent Node
 
-	Name      scanner.Token
-	Type      types.Type
-	InferType bool
-	Cases     []EnumCase
+	Attributes []Attribute
+	Docs       string
+	Pub        bool
+	Namespace  string
+	Name       scanner.Token
+	Type       types.Type
+	InferType  bool
+	Cases      []EnumCase
 }
 
 func (e *Enum) Token() scanner.Token {
@@ -286,12 +317,21 @@ func (e *Enum) String() string {
 	return e.Token().Lexeme
 }
 
+func (e *Enum) GetAttributes() []Attribute {
+	return e.Attributes
+}
+
+func (e *Enum) SetAttributes(attributes []Attribute) {
+	e.Attributes = attributes
+}
+
 func (e *Enum) SetChildrenParent() {
 }
 
 // EnumCase
 
 type EnumCase struct {
+	Docs       string
 	Name       scanner.Token
 	Value      int
 	InferValue bool
@@ -303,7 +343,10 @@ type Func struct {
 	range_ core.Range
 	parent Node
 
-	Attributes []any
+	Attributes []Attribute
+	Docs       string
+	Pub        bool
+	Namespace  string
 	Flags      FuncFlags
 	Name       scanner.Token
 	Params     []Param
@@ -385,6 +428,14 @@ func (f *Func) Leaf() bool {
 	return false
 }
 
+func (f *Func) GetAttributes() []Attribute {
+	return f.Attributes
+}
+
+func (f *Func) SetAttributes(attributes []Attribute) {
+	f.Attributes = attributes
+}
+
 func (f *Func) SetChildrenParent() {
 	for i_ := range f.Body {
 		if f.Body[i_] != nil {
@@ -400,11 +451,104 @@ type FuncFlags uint8
 const (
 	Static   FuncFlags = 1 << 0
 	Variadic FuncFlags = 1 << 1
+	Bound    FuncFlags = 1 << 2
+	Const    FuncFlags = 1 << 3
 )
 
 // Param
 
 type Param struct {
-	Name scanner.Token
-	Type types.Type
+	Attributes []Attribute
+	Name       scanner.Token
+	Type       types.Type
+}
+
+// StaticAssert
+
+type StaticAssert struct {
+	range_ core.Range
+	parent Node
+
+	Attributes []Attribute
+	Token_     scanner.Token
+	Condition  Expr
+	Message    scanner.Token
+}
+
+func (s *StaticAssert) Token() scanner.Token {
+	return s.Token_
+}
+
+func (s *StaticAssert) Range() core.Range {
+	return s.range_
+}
+
+func (s *StaticAssert) SetRangeToken(start, end scanner.Token) {
+	s.range_ = core.Range{
+		Start: core.TokenToPos(start, false),
+		End:   core.TokenToPos(end, true),
+	}
+}
+
+func (s *StaticAssert) SetRangePos(start, end core.Pos) {
+	s.range_ = core.Range{
+		Start: start,
+		End:   end,
+	}
+}
+
+func (s *StaticAssert) SetRangeNode(start, end Node) {
+	s.range_ = core.Range{
+		Start: start.Range().Start,
+		End:   end.Range().End,
+	}
+}
+
+func (s *StaticAssert) Parent() Node {
+	return s.parent
+}
+
+func (s *StaticAssert) SetParent(parent Node) {
+	if s.parent != nil && parent != nil {
+		log.Fatalln("StaticAssert.SetParent() - Node already has a parent")
+	}
+	s.parent = parent
+}
+
+func (s *StaticAssert) Accept(visitor DeclVisitor) {
+	visitor.VisitStaticAssert(s)
+}
+
+func (s *StaticAssert) AcceptChildren(visitor Acceptor) {
+	if s.Condition != nil {
+		visitor.AcceptExpr(s.Condition)
+	}
+}
+
+func (s *StaticAssert) AcceptTypes(visitor types.Visitor) {
+}
+
+func (s *StaticAssert) AcceptTypesPtr(visitor types.PtrVisitor) {
+}
+
+func (s *StaticAssert) Leaf() bool {
+	return false
+}
+
+func (s *StaticAssert) String() string {
+	return s.Token().Lexeme
+}
+
+func (s *StaticAssert) GetAttributes() []Attribute {
+	return s.Attributes
+}
+
+func (s *StaticAssert) SetAttributes(attributes []Attribute) {
+	s.Attributes = attributes
+}
+
+func (s *StaticAssert) SetChildrenParent() {
+	if s.Condition != nil {
+		s.Condition.SetParent(s)
+	}
 }
@@ -3,6 +3,7 @@ package ast
 import (
 	"fireball/core"
 	"fireball/core/types"
+	"go/constant"
 )
 
 type ExprResultKind = uint8
@@ -19,6 +20,13 @@ type ExprResultFlags = uint8
 const (
 	AssignableFlag ExprResultFlags = 1 << iota
 	AddressableFlag
+
+	// UntypedFlag marks untyped constants, their type is only the default one until the context converts them
+	UntypedFlag
+
+	// ImmutableFlag marks addressable values that cannot be written to, 'let' variables and values behind '*const'
+	// pointers
+	ImmutableFlag
 )
 
 type ExprResult struct {
@@ -27,6 +35,9 @@ type ExprResult struct {
 
 	Type     types.Type
 	Function *Func
+
+	// Constant is the folded value of an untyped constant expression, it is emitted in place of the expression
+	Constant constant.Value
 }
 
 func (e *ExprResult) IsAssignable() bool {
@@ -37,17 +48,27 @@ func (e *ExprResult) IsAddressable() bool {
 	return e.Flags&AddressableFlag != 0
 }
 
+func (e *ExprResult) IsUntyped() bool {
+	return e.Flags&UntypedFlag != 0
+}
+
+func (e *ExprResult) IsImmutable() bool {
+	return e.Flags&ImmutableFlag != 0
+}
+
 // Set
 
 func (e *ExprResult) SetInvalid() {
 	e.Kind = InvalidResultKind
 	e.Flags = 0
+	e.Constant = nil
 }
 
 func (e *ExprResult) SetType(type_ types.Type) {
 	e.Kind = TypeResultKind
 	e.Flags = 0
 	e.Type = type_
+	e.Constant = nil
 }
 
 func (e *ExprResult) SetFunction(function *Func) {
@@ -55,10 +76,12 @@ func (e *ExprResult) SetFunction(function *Func) {
 	e.Flags = 0
 	e.Type = function.WithRange(core.Range{})
 	e.Function = function
+	e.Constant = nil
 }
 
 func (e *ExprResult) SetValue(type_ types.Type, flags ExprResultFlags) {
 	e.Kind = ValueResultKind
 	e.Flags = flags
 	e.Type = type_.WithRange(core.Range{})
+	e.Constant = nil
 }
@@ -16,10 +16,13 @@ type ExprVisitor interface {
 	VisitUnary(expr *Unary)
 	VisitBinary(expr *Binary)
 	VisitLogical(expr *Logical)
+	VisitIfExpr(expr *IfExpr)
+	VisitBlockExpr(expr *BlockExpr)
 	VisitIdentifier(expr *Identifier)
 	VisitAssignment(expr *Assignment)
 	VisitCast(expr *Cast)
 	VisitTypeCall(expr *TypeCall)
+	VisitAssert(expr *Assert)
 	VisitCall(expr *Call)
 	VisitIndex(expr *Index)
 	VisitMember(expr *Member)
@@ -779,6 +782,209 @@ func (l *Logical) SetChildrenParent() {
 	}
 }
 
+// IfExpr
+
+type IfExpr struct {
+	range_ core.Range
+	parent Node
+	result ExprResult
+
+	Token_    scanner.Token
+	Condition Expr
+	Then      Expr
+	Else      Expr
+}
+
+func (i *IfExpr) Token() scanner.Token {
+	return i.Token_
+}
+
+func (i *IfExpr) Range() core.Range {
+	return i.range_
+}
+
+func (i *IfExpr) SetRangeToken(start, end scanner.Token) {
+	i.range_ = core.Range{
+		Start: core.TokenToPos(start, false),
+		End:   core.TokenToPos(end, true),
+	}
+}
+
+func (i *IfExpr) SetRangePos(start, end core.Pos) {
+	i.range_ = core.Range{
+		Start: start,
+		End:   end,
+	}
+}
+
+func (i *IfExpr) SetRangeNode(start, end Node) {
+	i.range_ = core.Range{
+		Start: start.Range().Start,
+		End:   end.Range().End,
+	}
+}
+
+func (i *IfExpr) Parent() Node {
+	return i.parent
+}
+
+func (i *IfExpr) SetParent(parent Node) {
+	if i.parent != nil && parent != nil {
+		log.Fatalln("IfExpr.SetParent() - Node already has a parent")
+	}
+	i.parent = parent
+}
+
+func (i *IfExpr) Accept(visitor ExprVisitor) {
+	visitor.VisitIfExpr(i)
+}
+
+func (i *IfExpr) AcceptChildren(visitor Acceptor) {
+	if i.Condition != nil {
+		visitor.AcceptExpr(i.Condition)
+	}
+	if i.Then != nil {
+		visitor.AcceptExpr(i.Then)
+	}
+	if i.Else != nil {
+		visitor.AcceptExpr(i.Else)
+	}
+}
+
+func (i *IfExpr) AcceptTypes(visitor types.Visitor) {
+	if i.result.Type != nil {
+		visitor.VisitType(i.result.Type)
+	}
+}
+
+func (i *IfExpr) AcceptTypesPtr(visitor types.PtrVisitor) {
+	visitor.VisitType(&i.result.Type)
+}
+
+func (i *IfExpr) Leaf() bool {
+	return false
+}
+
+func (i *IfExpr) String() string {
+	return i.Token().Lexeme
+}
+
+func (i *IfExpr) Result() *ExprResult {
+	return &i.result
+}
+
+func (i *IfExpr) SetChildrenParent() {
+	if i.Condition != nil {
+		i.Condition.SetParent(i)
+	}
+	if i.Then != nil {
+		i.Then.SetParent(i)
+	}
+	if i.Else != nil {
+		i.Else.SetParent(i)
+	}
+}
+
+// BlockExpr
+
+type BlockExpr struct {
+	range_ core.Range
+	parent Node
+	result ExprResult
+
+	Token_ scanner.Token
+	Stmts  []Stmt
+	Value  Expr
+}
+
+func (b *BlockExpr) Token() scanner.Token {
+	return b.Token_
+}
+
+func (b *BlockExpr) Range() core.Range {
+	return b.range_
+}
+
+func (b *BlockExpr) SetRangeToken(start, end scanner.Token) {
+	b.range_ = core.Range{
+		Start: core.TokenToPos(start, false),
+		End:   core.TokenToPos(end, true),
+	}
+}
+
+func (b *BlockExpr) SetRangePos(start, end core.Pos) {
+	b.range_ = core.Range{
+		Start: start,
+		End:   end,
+	}
+}
+
+func (b *BlockExpr) SetRangeNode(start, end Node) {
+	b.range_ = core.Range{
+		Start: start.Range().Start,
+		End:   end.Range().End,
+	}
+}
+
+func (b *BlockExpr) Parent() Node {
+	return b.parent
+}
+
+func (b *BlockExpr) SetParent(parent Node) {
+	if b.parent != nil && parent != nil {
+		log.Fatalln("BlockExpr.SetParent() - Node already has a parent")
+	}
+	b.parent = parent
+}
+
+func (b *BlockExpr) Accept(visitor ExprVisitor) {
+	visitor.VisitBlockExpr(b)
+}
+
+func (b *BlockExpr) AcceptChildren(visitor Acceptor) {
+	for i_ := range b.Stmts {
+		if b.Stmts[i_] != nil {
+			visitor.AcceptStmt(b.Stmts[i_])
+		}
+	}
+	if b.Value != nil {
+		visitor.AcceptExpr(b.Value)
+	}
+}
+
+func (b *BlockExpr) AcceptTypes(visitor types.Visitor) {
+	if b.result.Type != nil {
+		visitor.VisitType(b.result.Type)
+	}
+}
+
+func (b *BlockExpr) AcceptTypesPtr(visitor types.PtrVisitor) {
+	visitor.VisitType(&b.result.Type)
+}
+
+func (b *BlockExpr) Leaf() bool {
+	return false
+}
+
+func (b *BlockExpr) String() string {
+	return b.Token().Lexeme
+}
+
+func (b *BlockExpr) Result() *ExprResult {
+	return &b.result
+}
+
+func (b *BlockExpr) SetChildrenParent() {
+	for i_ := range b.Stmts {
+		if b.Stmts[i_] != nil {
+			b.Stmts[i_].SetParent(b)
+		}
+	}
+	if b.Value != nil {
+		b.Value.SetParent(b)
+	}
+}
+
 // Identifier
 
 type Identifier struct {
@@ -977,9 +1183,10 @@ type Cast struct {
 	parent Node
 	result ExprResult
 
-	Token_ scanner.Token
-	Target types.Type
-	Expr   Expr
+	Token_   scanner.Token
+	Target   types.Type
+	Expr     Expr
+	Implicit bool
 }
 
 func (c *Cast) Token() scanner.Token {
@@ -1151,6 +1358,96 @@ func (t *TypeCall) Result() *ExprResult {
 func (t *TypeCall) SetChildrenParent() {
 }
 
+// Assert
+
+type Assert struct {
+	range_ core.Range
+	parent Node
+	result ExprResult
+
+	Token_    scanner.Token
+	Condition Expr
+	Message   scanner.Token
+}
+
+func (a *Assert) Token() scanner.Token {
+	return a.Token_
+}
+
+func (a *Assert) Range() core.Range {
+	return a.range_
+}
+
+func (a *Assert) SetRangeToken(start, end scanner.Token) {
+	a.range_ = core.Range{
+		Start: core.TokenToPos(start, false),
+		End:   core.TokenToPos(end, true),
+	}
+}
+
+func (a *Assert) SetRangePos(start, end core.Pos) {
+	a.range_ = core.Range{
+		Start: start,
+		End:   end,
+	}
+}
+
+func (a *Assert) SetRangeNode(start, end Node) {
+	a.range_ = core.Range{
+		Start: start.Range().Start,
+		End:   end.Range().End,
+	}
+}
+
+func (a *Assert) Parent() Node {
+	return a.parent
+}
+
+func (a *Assert) SetParent(parent Node) {
+	if a.parent != nil && parent != nil {
+		log.Fatalln("Assert.SetParent() - Node already has a parent")
+	}
+	a.parent = parent
+}
+
+func (a *Assert) Accept(visitor ExprVisitor) {
+	visitor.VisitAssert(a)
+}
+
+func (a *Assert) AcceptChildren(visitor Acceptor) {
+	if a.Condition != nil {
+		visitor.AcceptExpr(a.Condition)
+	}
+}
+
+func (a *Assert) AcceptTypes(visitor types.Visitor) {
+	if a.result.Type != nil {
+		visitor.VisitType(a.result.Type)
+	}
+}
+
+func (a *Assert) AcceptTypesPtr(visitor types.PtrVisitor) {
+	visitor.VisitType(&a.result.Type)
+}
+
+func (a *Assert) Leaf() bool {
+	return false
+}
+
+func (a *Assert) String() string {
+	return a.Token().Lexeme
+}
+
+func (a *Assert) Result() *ExprResult {
+	return &a.result
+}
+
+func (a *Assert) SetChildrenParent() {
+	if a.Condition != nil {
+		a.Condition.SetParent(a)
+	}
+}
+
 // Call
 
 type Call struct {
@@ -1354,8 +1651,9 @@ type Member struct {
 	parent Node
 	result ExprResult
 
-	Value Expr
-	Name  scanner.Token
+	Value    Expr
+	Name     scanner.Token
+	Implicit bool
 }
 
 func (m *Member) Token() scanner.Token {
@@ -1,12 +1,19 @@
 package ast
 
 import (
+	"fireball/core/scanner"
 	"fireball/core/types"
 	"fmt"
-	"reflect"
+	"slices"
 	"strings"
 )
 
+// QualifiedName returns the name of the struct prefixed with the namespace of the project it is declared in, names
+// are only unique within a project.
+func (s *Struct) QualifiedName() string {
+	return qualifiedName(s.Namespace, s.Name.Lexeme)
+}
+
 func (s *Struct) GetStaticField(name string) (int, *Field) {
 	for i := range s.StaticFields {
 		field := &s.StaticFields[i]
@@ -31,6 +38,84 @@ func (s *Struct) GetField(name string) (int, *Field) {
 	return 0, nil
 }
 
+// Embedding is a struct reachable through the embedded fields of another struct.
+type Embedding struct {
+	Struct *Struct
+	Path   []*Field
+}
+
+// GetEmbedded returns the structs reachable through embedded fields grouped by their depth. A struct embedded multiple
+// times at the same depth is returned for each embedding but only searched once.
+func (s *Struct) GetEmbedded() [][]Embedding {
+	levels := make([][]Embedding, 0)
+
+	level := []Embedding{{Struct: s}}
+	visited := map[string]bool{s.Name.Lexeme: true}
+
+	for len(level) > 0 {
+		next := make([]Embedding, 0)
+		searched := make(map[string]bool)
+
+		for _, embedding := range level {
+			if searched[embedding.Struct.Name.Lexeme] {
+				continue
+			}
+
+			searched[embedding.Struct.Name.Lexeme] = true
+
+			for i := range embedding.Struct.Fields {
+				field := &embedding.Struct.Fields[i]
+				struct_, ok := field.Type.(*Struct)
+
+				if !field.Embedded || !ok || visited[struct_.Name.Lexeme] {
+					continue
+				}
+
+				next = append(next, Embedding{
+					Struct: struct_,
+					Path:   append(slices.Clone(embedding.Path), field),
+				})
+			}
+		}
+
+		for _, embedding := range next {
+			visited[embedding.Struct.Name.Lexeme] = true
+		}
+
+		if len(next) > 0 {
+			levels = append(levels, next)
+		}
+
+		level = next
+	}
+
+	return levels
+}
+
+// GetEmbeddedPath returns the embedded fields leading to the embedded struct with the same name as other. Returns nil
+// if the struct is not embedded or embedded multiple times at the shallowest depth.
+func (s *Struct) GetEmbeddedPath(other *Struct) []*Field {
+	for _, level := range s.GetEmbedded() {
+		var path []*Field
+		count := 0
+
+		for _, embedding := range level {
+			if embedding.Struct.Name.Lexeme == other.Name.Lexeme {
+				path = embedding.Path
+				count++
+			}
+		}
+
+		if count == 1 {
+			return path
+		} else if count > 1 {
+			return nil
+		}
+	}
+
+	return nil
+}
+
 func (i *Impl) GetMethod(name string, static bool) *Func {
 	staticValue := FuncFlags(0)
 	if static {
@@ -60,8 +145,21 @@ func (e *Enum) GetCase(name string) *EnumCase {
 	return nil
 }
 
+// QualifiedName returns the name of the enum prefixed with the namespace of the project it is declared in.
+func (e *Enum) QualifiedName() string {
+	return qualifiedName(e.Namespace, e.Name.Lexeme)
+}
+
 func (f *Field) GetMangledName() string {
-	return fmt.Sprintf("fb$%s::%s", f.Parent, f.Name)
+	return fmt.Sprintf("fb$%s::%s", f.Parent.QualifiedName(), f.Name)
+}
+
+func qualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+
+	return namespace + "." + name
 }
 
 // Func
@@ -74,19 +172,18 @@ func (f *Func) IsVariadic() bool {
 	return f.Flags&Variadic != 0
 }
 
-func (f *Func) GetAttribute(attribute any) bool {
-	value := reflect.ValueOf(attribute)
-	elem := value.Elem()
-	type_ := elem.Type()
-
-	for _, attr := range f.Attributes {
-		if reflect.TypeOf(attr) == type_ {
-			elem.Set(reflect.ValueOf(attr))
-			return true
-		}
-	}
+// IsConst returns true for methods which can't modify 'this', only they can be called on immutable values.
+func (f *Func) IsConst() bool {
+	return f.Flags&Const != 0
+}
 
-	return false
+// IsBound returns true for bound method types, their values remember the instance the method is called on.
+func (f *Func) IsBound() bool {
+	return f.Flags&Bound != 0
+}
+
+func (f *Func) GetAttribute(attribute any) bool {
+	return GetAttribute(f, attribute)
 }
 
 func (f *Func) HasBody() bool {
@@ -107,8 +204,12 @@ func (f *Func) Signature(paramNames bool) string {
 	signature := strings.Builder{}
 	signature.WriteRune('(')
 
+	if f.IsBound() {
+		signature.WriteString("this")
+	}
+
 	for i, param := range f.Params {
-		if i > 0 {
+		if i > 0 || f.IsBound() {
 			signature.WriteString(", ")
 		}
 
@@ -138,6 +239,32 @@ func (f *Func) Method() *Struct {
 	return nil
 }
 
+// Reference returns the type of a method reference. Bound references remember the instance and take the same
+// parameters as the method, unbound references take the instance as an explicit first 'this' parameter.
+func (f *Func) Reference(bound bool) *Func {
+	if bound {
+		return &Func{
+			Flags:   Bound | f.Flags&Variadic,
+			Params:  f.Params,
+			Returns: f.Returns,
+		}
+	}
+
+	params := make([]Param, 0, len(f.Params)+1)
+
+	params = append(params, Param{
+		Name: scanner.Token{Kind: scanner.Identifier, Lexeme: "this"},
+		Type: &types.PointerType{Pointee: f.Method()},
+	})
+	params = append(params, f.Params...)
+
+	return &Func{
+		Flags:   f.Flags & Variadic,
+		Params:  params,
+		Returns: f.Returns,
+	}
+}
+
 func (f *Func) MangledName() string {
 	// Extern
 	var extern types.ExternAttribute
@@ -145,12 +272,23 @@ func (f *Func) MangledName() string {
 		return extern.Name
 	}
 
-	// Normal
+	// Export
+	var export types.ExportAttribute
+	if f.GetAttribute(&export) {
+		return export.Name
+	}
+
+	// Normal, methods are in the namespace of their struct
 	name := f.Name.Lexeme
+	namespace := f.Namespace
 
 	if struct_, ok := f.Parent().(*Impl); ok {
 		name = fmt.Sprintf("%s.%s", struct_, name)
+
+		if struct_.Type_ != nil {
+			namespace = struct_.Type_.Namespace
+		}
 	}
 
-	return "fb$" + name
+	return "fb$" + qualifiedName(namespace, name)
 }
@@ -32,7 +32,11 @@ func (p *printer) VisitStruct(decl *Struct) {
 	p.print("struct %s", decl.Name)
 
 	for _, field := range decl.Fields {
-		p.print("%s %s", field.Name, field.Type)
+		if field.Embedded {
+			p.print("%s", field.Name)
+		} else {
+			p.print("%s %s", field.Name, field.Type)
+		}
 	}
 }
 
@@ -84,6 +88,11 @@ func (p *printer) VisitFunc(decl *Func) {
 	}
 }
 
+func (p *printer) VisitStaticAssert(decl *StaticAssert) {
+	p.print("static_assert %s", decl.Message)
+	p.AcceptExpr(decl.Condition)
+}
+
 // Statements
 
 func (p *printer) VisitBlock(stmt *Block) {
@@ -104,6 +113,22 @@ func (p *printer) VisitVariable(stmt *Variable) {
 	p.AcceptExpr(stmt.Initializer)
 }
 
+func (p *printer) VisitDestructure(stmt *Destructure) {
+	if stmt.Array {
+		p.print("[]")
+	} else if stmt.Type != nil {
+		p.print("%s {}", stmt.Type)
+	} else {
+		p.print("{}")
+	}
+
+	for _, binding := range stmt.Bindings {
+		p.AcceptStmt(binding)
+	}
+
+	p.AcceptExpr(stmt.Initializer)
+}
+
 func (p *printer) VisitIf(stmt *If) {
 	p.print("if")
 	p.AcceptExpr(stmt.Condition)
@@ -132,6 +157,11 @@ func (p *printer) VisitContinue(stmt *Continue) {
 	p.print("continue")
 }
 
+func (p *printer) VisitStaticAssertStmt(stmt *StaticAssertStmt) {
+	p.print("static_assert %s", stmt.Message)
+	p.AcceptExpr(stmt.Condition)
+}
+
 // Expressions
 
 func (p *printer) VisitGroup(expr *Group) {
@@ -188,6 +218,23 @@ func (p *printer) VisitLogical(expr *Logical) {
 	p.AcceptExpr(expr.Right)
 }
 
+func (p *printer) VisitIfExpr(expr *IfExpr) {
+	p.print("if")
+	p.AcceptExpr(expr.Condition)
+	p.AcceptExpr(expr.Then)
+	p.AcceptExpr(expr.Else)
+}
+
+func (p *printer) VisitBlockExpr(expr *BlockExpr) {
+	p.print("{}")
+
+	for _, s := range expr.Stmts {
+		p.AcceptStmt(s)
+	}
+
+	p.AcceptExpr(expr.Value)
+}
+
 func (p *printer) VisitIdentifier(expr *Identifier) {
 	p.print(expr.Identifier.Lexeme)
 }
@@ -207,6 +254,11 @@ func (p *printer) VisitTypeCall(expr *TypeCall) {
 	p.print("sizeof %s", expr.Target)
 }
 
+func (p *printer) VisitAssert(expr *Assert) {
+	p.print("assert")
+	p.AcceptExpr(expr.Condition)
+}
+
 func (p *printer) VisitCall(expr *Call) {
 	p.print("call")
 	p.AcceptExpr(expr.Callee)
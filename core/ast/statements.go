@@ -11,11 +11,13 @@ type StmtVisitor interface {
 	VisitBlock(stmt *Block)
 	VisitExpression(stmt *Expression)
 	VisitVariable(stmt *Variable)
+	VisitDestructure(stmt *Destructure)
 	VisitIf(stmt *If)
 	VisitFor(stmt *For)
 	VisitReturn(stmt *Return)
 	VisitBreak(stmt *Break)
 	VisitContinue(stmt *Continue)
+	VisitStaticAssertStmt(stmt *StaticAssertStmt)
 }
 
 type Stmt interface {
@@ -30,8 +32,9 @@ type Block struct {
 	range_ core.Range
 	parent Node
 
-	Token_ scanner.Token
-	Stmts  []Stmt
+	Attributes []Attribute
+	Token_     scanner.Token
+	Stmts      []Stmt
 }
 
 func (b *Block) Token() scanner.Token {
@@ -100,6 +103,14 @@ func (b *Block) String() string {
 	return b.Token().Lexeme
 }
 
+func (b *Block) GetAttributes() []Attribute {
+	return b.Attributes
+}
+
+func (b *Block) SetAttributes(attributes []Attribute) {
+	b.Attributes = attributes
+}
+
 func (b *Block) SetChildrenParent() {
 	for i_ := range b.Stmts {
 		if b.Stmts[i_] != nil {
@@ -114,8 +125,9 @@ type Expression struct {
 	range_ core.Range
 	parent Node
 
-	Token_ scanner.Token
-	Expr   Expr
+	Attributes []Attribute
+	Token_     scanner.Token
+	Expr       Expr
 }
 
 func (e *Expression) Token() scanner.Token {
@@ -182,6 +194,14 @@ func (e *Expression) String() string {
 	return e.Token().Lexeme
 }
 
+func (e *Expression) GetAttributes() []Attribute {
+	return e.Attributes
+}
+
+func (e *Expression) SetAttributes(attributes []Attribute) {
+	e.Attributes = attributes
+}
+
 func (e *Expression) SetChildrenParent() {
 	if e.Expr != nil {
 		e.Expr.SetParent(e)
@@ -194,10 +214,12 @@ type Variable struct {
 	range_ core.Range
 	parent Node
 
+	Attributes  []Attribute
 	Type        types.Type
 	Name        scanner.Token
 	Initializer Expr
 	InferType   bool
+	Immutable   bool
 }
 
 func (v *Variable) Token() scanner.Token {
@@ -268,22 +290,137 @@ func (v *Variable) String() string {
 	return v.Token().Lexeme
 }
 
+func (v *Variable) GetAttributes() []Attribute {
+	return v.Attributes
+}
+
+func (v *Variable) SetAttributes(attributes []Attribute) {
+	v.Attributes = attributes
+}
+
 func (v *Variable) SetChildrenParent() {
 	if v.Initializer != nil {
 		v.Initializer.SetParent(v)
 	}
 }
 
+// Destructure
+
+type Destructure struct {
+	range_ core.Range
+	parent Node
+
+	Attributes  []Attribute
+	Token_      scanner.Token
+	Type        types.Type
+	Array       bool
+	Initializer Expr
+	Bindings    []Stmt
+}
+
+func (d *Destructure) Token() scanner.Token {
+	return d.Token_
+}
+
+func (d *Destructure) Range() core.Range {
+	return d.range_
+}
+
+func (d *Destructure) SetRangeToken(start, end scanner.Token) {
+	d.range_ = core.Range{
+		Start: core.TokenToPos(start, false),
+		End:   core.TokenToPos(end, true),
+	}
+}
+
+func (d *Destructure) SetRangePos(start, end core.Pos) {
+	d.range_ = core.Range{
+		Start: start,
+		End:   end,
+	}
+}
+
+func (d *Destructure) SetRangeNode(start, end Node) {
+	d.range_ = core.Range{
+		Start: start.Range().Start,
+		End:   end.Range().End,
+	}
+}
+
+func (d *Destructure) Parent() Node {
+	return d.parent
+}
+
+func (d *Destructure) SetParent(parent Node) {
+	if d.parent != nil && parent != nil {
+		log.Fatalln("Destructure.SetParent() - Node already has a parent")
+	}
+	d.parent = parent
+}
+
+func (d *Destructure) Accept(visitor StmtVisitor) {
+	visitor.VisitDestructure(d)
+}
+
+func (d *Destructure) AcceptChildren(visitor Acceptor) {
+	if d.Initializer != nil {
+		visitor.AcceptExpr(d.Initializer)
+	}
+	for i_ := range d.Bindings {
+		if d.Bindings[i_] != nil {
+			visitor.AcceptStmt(d.Bindings[i_])
+		}
+	}
+}
+
+func (d *Destructure) AcceptTypes(visitor types.Visitor) {
+	if d.Type != nil {
+		visitor.VisitType(d.Type)
+	}
+}
+
+func (d *Destructure) AcceptTypesPtr(visitor types.PtrVisitor) {
+	visitor.VisitType(&d.Type)
+}
+
+func (d *Destructure) Leaf() bool {
+	return false
+}
+
+func (d *Destructure) String() string {
+	return d.Token().Lexeme
+}
+
+func (d *Destructure) GetAttributes() []Attribute {
+	return d.Attributes
+}
+
+func (d *Destructure) SetAttributes(attributes []Attribute) {
+	d.Attributes = attributes
+}
+
+func (d *Destructure) SetChildrenParent() {
+	if d.Initializer != nil {
+		d.Initializer.SetParent(d)
+	}
+	for i_ := range d.Bindings {
+		if d.Bindings[i_] != nil {
+			d.Bindings[i_].SetParent(d)
+		}
+	}
+}
+
 // If
 
 type If struct {
 	range_ core.Range
 	parent Node
 
-	Token_    scanner.Token
-	Condition Expr
-	Then      Stmt
-	Else      Stmt
+	Attributes []Attribute
+	Token_     scanner.Token
+	Condition  Expr
+	Then       Stmt
+	Else       Stmt
 }
 
 func (i *If) Token() scanner.Token {
@@ -356,6 +493,14 @@ func (i *If) String() string {
 	return i.Token().Lexeme
 }
 
+func (i *If) GetAttributes() []Attribute {
+	return i.Attributes
+}
+
+func (i *If) SetAttributes(attributes []Attribute) {
+	i.Attributes = attributes
+}
+
 func (i *If) SetChildrenParent() {
 	if i.Condition != nil {
 		i.Condition.SetParent(i)
@@ -374,6 +519,7 @@ type For struct {
 	range_ core.Range
 	parent Node
 
+	Attributes  []Attribute
 	Token_      scanner.Token
 	Initializer Stmt
 	Condition   Expr
@@ -454,6 +600,14 @@ func (f *For) String() string {
 	return f.Token().Lexeme
 }
 
+func (f *For) GetAttributes() []Attribute {
+	return f.Attributes
+}
+
+func (f *For) SetAttributes(attributes []Attribute) {
+	f.Attributes = attributes
+}
+
 func (f *For) SetChildrenParent() {
 	if f.Initializer != nil {
 		f.Initializer.SetParent(f)
@@ -475,8 +629,9 @@ type Return struct {
 	range_ core.Range
 	parent Node
 
-	Token_ scanner.Token
-	Expr   Expr
+	Attributes []Attribute
+	Token_     scanner.Token
+	Expr       Expr
 }
 
 func (r *Return) Token() scanner.Token {
@@ -543,6 +698,14 @@ func (r *Return) String() string {
 	return r.Token().Lexeme
 }
 
+func (r *Return) GetAttributes() []Attribute {
+	return r.Attributes
+}
+
+func (r *Return) SetAttributes(attributes []Attribute) {
+	r.Attributes = attributes
+}
+
 func (r *Return) SetChildrenParent() {
 	if r.Expr != nil {
 		r.Expr.SetParent(r)
@@ -555,7 +718,8 @@ type Break struct {
 	range_ core.Range
 	parent Node
 
-	Token_ scanner.Token
+	Attributes []Attribute
+	Token_     scanner.Token
 }
 
 func (b *Break) Token() scanner.Token {
@@ -619,6 +783,14 @@ func (b *Break) String() string {
 	return b.Token().Lexeme
 }
 
+func (b *Break) GetAttributes() []Attribute {
+	return b.Attributes
+}
+
+func (b *Break) SetAttributes(attributes []Attribute) {
+	b.Attributes = attributes
+}
+
 func (b *Break) SetChildrenParent() {
 }
 
@@ -628,7 +800,8 @@ type Continue struct {
 	range_ core.Range
 	parent Node
 
-	Token_ scanner.Token
+	Attributes []Attribute
+	Token_     scanner.Token
 }
 
 func (c *Continue) Token() scanner.Token {
@@ -692,5 +865,103 @@ func (c *Continue) String() string {
 	return c.Token().Lexeme
 }
 
+func (c *Continue) GetAttributes() []Attribute {
+	return c.Attributes
+}
+
+func (c *Continue) SetAttributes(attributes []Attribute) {
+	c.Attributes = attributes
+}
+
 func (c *Continue) SetChildrenParent() {
 }
+
+// StaticAssertStmt
+
+type StaticAssertStmt struct {
+	range_ core.Range
+	parent Node
+
+	Attributes []Attribute
+	Token_     scanner.Token
+	Condition  Expr
+	Message    scanner.Token
+}
+
+func (s *StaticAssertStmt) Token() scanner.Token {
+	return s.Token_
+}
+
+func (s *StaticAssertStmt) Range() core.Range {
+	return s.range_
+}
+
+func (s *StaticAssertStmt) SetRangeToken(start, end scanner.Token) {
+	s.range_ = core.Range{
+		Start: core.TokenToPos(start, false),
+		End:   core.TokenToPos(end, true),
+	}
+}
+
+func (s *StaticAssertStmt) SetRangePos(start, end core.Pos) {
+	s.range_ = core.Range{
+		Start: start,
+		End:   end,
+	}
+}
+
+func (s *StaticAssertStmt) SetRangeNode(start, end Node) {
+	s.range_ = core.Range{
+		Start: start.Range().Start,
+		End:   end.Range().End,
+	}
+}
+
+func (s *StaticAssertStmt) Parent() Node {
+	return s.parent
+}
+
+func (s *StaticAssertStmt) SetParent(parent Node) {
+	if s.parent != nil && parent != nil {
+		log.Fatalln("StaticAssertStmt.SetParent() - Node already has a parent")
+	}
+	s.parent = parent
+}
+
+func (s *StaticAssertStmt) Accept(visitor StmtVisitor) {
+	visitor.VisitStaticAssertStmt(s)
+}
+
+func (s *StaticAssertStmt) AcceptChildren(visitor Acceptor) {
+	if s.Condition != nil {
+		visitor.AcceptExpr(s.Condition)
+	}
+}
+
+func (s *StaticAssertStmt) AcceptTypes(visitor types.Visitor) {
+}
+
+func (s *StaticAssertStmt) AcceptTypesPtr(visitor types.PtrVisitor) {
+}
+
+func (s *StaticAssertStmt) Leaf() bool {
+	return false
+}
+
+func (s *StaticAssertStmt) String() string {
+	return s.Token().Lexeme
+}
+
+func (s *StaticAssertStmt) GetAttributes() []Attribute {
+	return s.Attributes
+}
+
+func (s *StaticAssertStmt) SetAttributes(attributes []Attribute) {
+	s.Attributes = attributes
+}
+
+func (s *StaticAssertStmt) SetChildrenParent() {
+	if s.Condition != nil {
+		s.Condition.SetParent(s)
+	}
+}
@@ -3,13 +3,14 @@ package ast
 import (
 	"fireball/core"
 	"fireball/core/architecture"
+	"fireball/core/target"
 	"fireball/core/types"
 )
 
 // Struct
 
-func (s *Struct) Size() int {
-	layout := architecture.CLayout{}
+func (s *Struct) Size(t target.Target) int {
+	layout := architecture.CLayout{Target: t}
 
 	for _, field := range s.Fields {
 		layout.Add(field.Type)
@@ -18,11 +19,11 @@ func (s *Struct) Size() int {
 	return layout.Size()
 }
 
-func (s *Struct) Align() int {
+func (s *Struct) Align(t target.Target) int {
 	biggest := 0
 
 	for _, field := range s.Fields {
-		biggest = max(biggest, field.Type.Align())
+		biggest = max(biggest, field.Type.Align(t))
 	}
 
 	return biggest
@@ -32,6 +33,10 @@ func (s *Struct) WithRange(range_ core.Range) types.Type {
 	return &Struct{
 		range_:       range_,
 		parent:       s.parent,
+		Attributes:   s.Attributes,
+		Docs:         s.Docs,
+		Pub:          s.Pub,
+		Namespace:    s.Namespace,
 		Name:         s.Name,
 		StaticFields: s.StaticFields,
 		Fields:       s.Fields,
@@ -61,24 +66,36 @@ func (s *Struct) CanAssignTo(other types.Type) bool {
 	return s.Equals(other)
 }
 
+func (s *Struct) Embeds(other types.Type) bool {
+	if v, ok := other.(*Struct); ok {
+		return s.GetEmbeddedPath(v) != nil
+	}
+
+	return false
+}
+
 // Enum
 
-func (e *Enum) Size() int {
-	return e.Type.Size()
+func (e *Enum) Size(t target.Target) int {
+	return e.Type.Size(t)
 }
 
-func (e *Enum) Align() int {
-	return e.Type.Align()
+func (e *Enum) Align(t target.Target) int {
+	return e.Type.Align(t)
 }
 
 func (e *Enum) WithRange(range_ core.Range) types.Type {
 	return &Enum{
-		range_:    range_,
-		parent:    e.parent,
-		Name:      e.Name,
-		Type:      e.Type,
-		InferType: e.InferType,
-		Cases:     e.Cases,
+		range_:     range_,
+		parent:     e.parent,
+		Attributes: e.Attributes,
+		Docs:       e.Docs,
+		Pub:        e.Pub,
+		Namespace:  e.Namespace,
+		Name:       e.Name,
+		Type:       e.Type,
+		InferType:  e.InferType,
+		Cases:      e.Cases,
 	}
 }
 
@@ -108,12 +125,16 @@ func (e *Enum) CanAssignTo(other types.Type) bool {
 
 // Function
 
-func (f *Func) Size() int {
-	return 8
+func (f *Func) Size(t target.Target) int {
+	if f.IsBound() {
+		return t.PointerSize() * 2
+	}
+
+	return t.PointerSize()
 }
 
-func (f *Func) Align() int {
-	return 8
+func (f *Func) Align(t target.Target) int {
+	return t.PointerSize()
 }
 
 func (f *Func) WithRange(range_ core.Range) types.Type {
@@ -121,6 +142,9 @@ func (f *Func) WithRange(range_ core.Range) types.Type {
 		range_:     range_,
 		parent:     f.parent,
 		Attributes: f.Attributes,
+		Docs:       f.Docs,
+		Pub:        f.Pub,
+		Namespace:  f.Namespace,
 		Flags:      f.Flags,
 		Name:       f.Name,
 		Params:     f.Params,
@@ -131,9 +155,15 @@ func (f *Func) WithRange(range_ core.Range) types.Type {
 
 func (f *Func) Equals(other types.Type) bool {
 	if v, ok := other.(*Func); ok {
+		if f.Flags != v.Flags {
+			return false
+		}
 		if f.Name.Lexeme != v.Name.Lexeme {
 			return false
 		}
+		if f.Namespace != v.Namespace {
+			return false
+		}
 		if f.Parent() != v.Parent() {
 			return false
 		}
@@ -159,7 +189,10 @@ func (f *Func) Equals(other types.Type) bool {
 
 func (f *Func) CanAssignTo(other types.Type) bool {
 	if v, ok := other.(*Func); ok {
-		if !f.Returns.CanAssignTo(v.Returns) {
+		if f.IsBound() != v.IsBound() {
+			return false
+		}
+		if !types.CanReinterpret(f.Returns, v.Returns) {
 			return false
 		}
 
@@ -168,7 +201,7 @@ func (f *Func) CanAssignTo(other types.Type) bool {
 		}
 
 		for i, param := range f.Params {
-			if !param.Type.CanAssignTo(v.Params[i].Type) {
+			if !types.CanReinterpret(param.Type, v.Params[i].Type) {
 				return false
 			}
 		}
@@ -22,6 +22,7 @@ type checker struct {
 	reporter utils.Reporter
 	resolver utils.Resolver
 	decls    []ast.Decl
+	usages   usages
 }
 
 type scope struct {
@@ -33,8 +34,9 @@ type variable struct {
 	name  scanner.Token
 	type_ types.Type
 
-	param bool
-	used  bool
+	param     bool
+	immutable bool
+	used      bool
 }
 
 func Check(reporter utils.Reporter, resolver utils.Resolver, decls []ast.Decl) {
@@ -42,6 +44,7 @@ func Check(reporter utils.Reporter, resolver utils.Resolver, decls []ast.Decl) {
 		reporter: reporter,
 		resolver: resolver,
 		decls:    decls,
+		usages:   newUsages(decls),
 	}
 
 	reset(c, decls)
@@ -49,6 +52,8 @@ func Check(reporter utils.Reporter, resolver utils.Resolver, decls []ast.Decl) {
 	for _, decl := range decls {
 		c.AcceptDecl(decl)
 	}
+
+	c.reportUnused()
 }
 
 // Scope / Variables
@@ -120,11 +125,19 @@ func (c *checker) AcceptDecl(decl ast.Decl) {
 }
 
 func (c *checker) AcceptStmt(stmt ast.Stmt) {
+	c.checkAttributes(stmt.(ast.Attributed).GetAttributes(), types.StmtTarget)
 	stmt.Accept(c)
+
+	stmt.AcceptChildren(untypedDefaulter{c: c})
 }
 
 func (c *checker) AcceptExpr(expr ast.Expr) {
 	expr.Accept(c)
+
+	// Untyped operands of folded or untyped expressions are converted together with them
+	if !expr.Result().IsUntyped() && expr.Result().Constant == nil {
+		expr.AcceptChildren(untypedDefaulter{c: c})
+	}
 }
 
 // Diagnostics
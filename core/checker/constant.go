@@ -0,0 +1,296 @@
+package checker
+
+import (
+	"fireball/core/ast"
+	"fireball/core/scanner"
+	"fireball/core/types"
+	"go/constant"
+	"go/token"
+	"math"
+)
+
+// evaluate computes the value of an already checked expression at compile time, returns an unknown value if the
+// expression is not constant.
+func (c *checker) evaluate(expr ast.Expr) constant.Value {
+	unknown := constant.MakeUnknown()
+
+	if expr == nil || expr.Result().Kind != ast.ValueResultKind {
+		return unknown
+	}
+
+	if expr.Result().Constant != nil {
+		return expr.Result().Constant
+	}
+
+	switch expr := expr.(type) {
+	case *ast.Group:
+		return c.evaluate(expr.Expr)
+
+	case *ast.Literal:
+		return evaluateLiteral(expr.Value)
+
+	case *ast.IfExpr:
+		if condition := c.evaluate(expr.Condition); condition.Kind() == constant.Bool {
+			if constant.BoolVal(condition) {
+				return c.evaluate(expr.Then)
+			}
+
+			return c.evaluate(expr.Else)
+		}
+
+	case *ast.BlockExpr:
+		// Statements can have side effects
+		if len(expr.Stmts) == 0 {
+			return c.evaluate(expr.Value)
+		}
+
+	case *ast.TypeCall:
+		switch expr.Name.Lexeme {
+		case "sizeof":
+			return constant.MakeInt64(int64(expr.Target.Size(c.resolver.GetTarget())))
+		case "alignof":
+			return constant.MakeInt64(int64(expr.Target.Align(c.resolver.GetTarget())))
+		}
+
+	case *ast.Member:
+		if expr.Value.Result().Kind == ast.TypeResultKind {
+			if enum, ok := expr.Value.Result().Type.(*ast.Enum); ok {
+				if case_ := enum.GetCase(expr.Name.Lexeme); case_ != nil {
+					return constant.MakeInt64(int64(case_.Value))
+				}
+			}
+		}
+
+	case *ast.Unary:
+		value := c.evaluate(expr.Value)
+
+		if expr.Prefix && value.Kind() != constant.Unknown {
+			switch expr.Op.Kind {
+			case scanner.Minus:
+				if value.Kind() == constant.Int || value.Kind() == constant.Float {
+					return narrowConstant(expr, expr.Op.Kind, constant.UnaryOp(token.SUB, value, 0))
+				}
+
+			case scanner.Bang:
+				if value.Kind() == constant.Bool {
+					return constant.UnaryOp(token.NOT, value, 0)
+				}
+			}
+		}
+
+	case *ast.Binary:
+		return narrowConstant(expr, expr.Op.Kind, evaluateBinary(expr.Op.Kind, c.evaluate(expr.Left), c.evaluate(expr.Right)))
+
+	case *ast.Logical:
+		left := c.evaluate(expr.Left)
+		right := c.evaluate(expr.Right)
+
+		if left.Kind() == constant.Bool && right.Kind() == constant.Bool {
+			if expr.Op.Kind == scanner.And {
+				return constant.BinaryOp(left, token.LAND, right)
+			}
+
+			return constant.BinaryOp(left, token.LOR, right)
+		}
+
+	case *ast.Cast:
+		return convertConstant(c.evaluate(expr.Expr), expr.Result().Type)
+	}
+
+	return unknown
+}
+
+func evaluateLiteral(value scanner.Token) constant.Value {
+	switch value.Kind {
+	case scanner.True:
+		return constant.MakeBool(true)
+	case scanner.False:
+		return constant.MakeBool(false)
+
+	case scanner.Number, scanner.Hex, scanner.Binary, scanner.Octal:
+		number, _ := scanner.ParseNumber(value)
+		return number
+
+	case scanner.Character:
+		if char, err := scanner.ParseCharacter(value); err == nil {
+			return constant.MakeInt64(int64(char))
+		}
+	}
+
+	return constant.MakeUnknown()
+}
+
+func evaluateBinary(op scanner.TokenKind, left, right constant.Value) constant.Value {
+	unknown := constant.MakeUnknown()
+
+	if left.Kind() == constant.Unknown || right.Kind() == constant.Unknown {
+		return unknown
+	}
+
+	// Mixed integer and float operands are computed as floats
+	if left.Kind() == constant.Int && right.Kind() == constant.Float {
+		left = constant.ToFloat(left)
+	} else if left.Kind() == constant.Float && right.Kind() == constant.Int {
+		right = constant.ToFloat(right)
+	}
+
+	if left.Kind() != right.Kind() {
+		return unknown
+	}
+
+	integer := left.Kind() == constant.Int
+
+	switch op {
+	// Arithmetic
+	case scanner.Plus, scanner.PlusPercentage:
+		return constant.BinaryOp(left, token.ADD, right)
+	case scanner.Minus, scanner.MinusPercentage:
+		return constant.BinaryOp(left, token.SUB, right)
+	case scanner.Star, scanner.StarPercentage:
+		return constant.BinaryOp(left, token.MUL, right)
+
+	case scanner.Slash, scanner.Percentage:
+		if constant.Sign(right) == 0 {
+			return unknown
+		}
+
+		if op == scanner.Percentage {
+			if !integer {
+				return unknown
+			}
+
+			return constant.BinaryOp(left, token.REM, right)
+		}
+
+		if integer {
+			return constant.BinaryOp(left, token.QUO_ASSIGN, right)
+		}
+
+		return constant.BinaryOp(left, token.QUO, right)
+
+	// Equality and comparison
+	case scanner.EqualEqual:
+		return constant.MakeBool(constant.Compare(left, token.EQL, right))
+	case scanner.BangEqual:
+		return constant.MakeBool(constant.Compare(left, token.NEQ, right))
+	case scanner.Less:
+		return constant.MakeBool(constant.Compare(left, token.LSS, right))
+	case scanner.LessEqual:
+		return constant.MakeBool(constant.Compare(left, token.LEQ, right))
+	case scanner.Greater:
+		return constant.MakeBool(constant.Compare(left, token.GTR, right))
+	case scanner.GreaterEqual:
+		return constant.MakeBool(constant.Compare(left, token.GEQ, right))
+
+	// Bitwise
+	case scanner.Pipe, scanner.Ampersand, scanner.Xor, scanner.LessLess, scanner.GreaterGreater:
+		if !integer {
+			return unknown
+		}
+
+		switch op {
+		case scanner.Pipe:
+			return constant.BinaryOp(left, token.OR, right)
+		case scanner.Ampersand:
+			return constant.BinaryOp(left, token.AND, right)
+		case scanner.Xor:
+			return constant.BinaryOp(left, token.XOR, right)
+		}
+
+		shift, ok := constant.Uint64Val(right)
+		if !ok || shift > math.MaxUint16 {
+			return unknown
+		}
+
+		if op == scanner.LessLess {
+			return constant.Shift(left, token.SHL, uint(shift))
+		}
+
+		return constant.Shift(left, token.SHR, uint(shift))
+	}
+
+	return unknown
+}
+
+// narrowConstant converts the result of a typed operation to its type. Wrapping and bitwise operators wrap around the
+// size of the type, checked arithmetic which overflows it panics at runtime and is not constant.
+func narrowConstant(expr ast.Expr, op scanner.TokenKind, value constant.Value) constant.Value {
+	if expr.Result().IsUntyped() || (value.Kind() != constant.Int && value.Kind() != constant.Float) {
+		return value
+	}
+
+	primitive, ok := expr.Result().Type.(*types.PrimitiveType)
+	if !ok {
+		return constant.MakeUnknown()
+	}
+
+	if !types.IsInteger(primitive.Kind) || scanner.IsWrapping(op) || scanner.IsBitwise(op) {
+		return convertConstant(value, primitive)
+	}
+
+	if converted, problem := fit(value, primitive); problem == "" {
+		return converted
+	}
+
+	return constant.MakeUnknown()
+}
+
+// checkConstantOverflow reports checked integer arithmetic on constants which overflows its type, it would always
+// panic at runtime.
+func (c *checker) checkConstantOverflow(expr ast.Expr, value constant.Value, type_ *types.PrimitiveType) {
+	if !types.IsInteger(type_.Kind) || value.Kind() != constant.Int {
+		return
+	}
+
+	// The wrapped value is kept so expressions using it do not cascade errors
+	if _, ok := c.representable(expr.Range(), value, type_); !ok {
+		expr.Result().Constant = convertConstant(value, type_)
+	}
+}
+
+func convertConstant(value constant.Value, type_ types.Type) constant.Value {
+	if enum, ok := type_.(*ast.Enum); ok {
+		type_ = enum.Type
+	}
+
+	primitive, ok := type_.(*types.PrimitiveType)
+	if !ok || value.Kind() == constant.Unknown {
+		return constant.MakeUnknown()
+	}
+
+	switch {
+	case types.IsFloating(primitive.Kind):
+		if value.Kind() == constant.Int || value.Kind() == constant.Float {
+			return constant.ToFloat(value)
+		}
+
+	case types.IsInteger(primitive.Kind):
+		// Truncate floats towards zero
+		if value.Kind() == constant.Float {
+			f, _ := constant.Float64Val(value)
+			value = constant.ToInt(constant.MakeFloat64(math.Trunc(f)))
+		}
+
+		if value.Kind() != constant.Int {
+			return constant.MakeUnknown()
+		}
+
+		// Wrap around the size of the target type
+		bits := uint(types.GetBitSize(primitive.Kind))
+
+		mask := constant.BinaryOp(constant.Shift(constant.MakeInt64(1), token.SHL, bits), token.SUB, constant.MakeInt64(1))
+		value = constant.BinaryOp(value, token.AND, mask)
+
+		if types.IsSigned(primitive.Kind) {
+			limit := constant.Shift(constant.MakeInt64(1), token.SHL, bits-1)
+
+			if constant.Compare(value, token.GEQ, limit) {
+				value = constant.BinaryOp(value, token.SUB, constant.Shift(limit, token.SHL, 1))
+			}
+		}
+
+		return value
+	}
+
+	return constant.MakeUnknown()
+}
@@ -5,15 +5,19 @@ import (
 	"fireball/core/scanner"
 	"fireball/core/types"
 	"fireball/core/utils"
+	"go/constant"
 )
 
 func (c *checker) VisitStruct(decl *ast.Struct) {
 	decl.AcceptChildren(c)
+	c.checkAttributes(decl.Attributes, types.StructTarget)
 
 	// Check static fields
 	fields := utils.NewSet[string]()
 
 	for _, field := range decl.StaticFields {
+		c.checkAttributes(field.Attributes, types.FieldTarget)
+
 		// Check name collision
 		if !fields.Add(field.Name.Lexeme) {
 			c.errorToken(field.Name, "Static field with the name '%s' already exists.", field.Name)
@@ -29,11 +33,22 @@ func (c *checker) VisitStruct(decl *ast.Struct) {
 	fields = utils.NewSet[string]()
 
 	for _, field := range decl.Fields {
+		c.checkAttributes(field.Attributes, types.FieldTarget)
+
 		// Check name collision
 		if !fields.Add(field.Name.Lexeme) {
 			c.errorToken(field.Name, "Field with the name '%s' already exists.", field.Name)
 		}
 
+		// Check embedded type, unknown types are already reported
+		if field.Embedded {
+			if _, ok := field.Type.(*ast.Struct); !ok && !types.IsPrimitive(field.Type, types.Void) {
+				c.errorToken(field.Name, "Embedded field needs to be a struct but got '%s'.", field.Type)
+			}
+
+			continue
+		}
+
 		// Check void type
 		if types.IsPrimitive(field.Type, types.Void) {
 			c.errorToken(field.Name, "Field cannot be of type 'void'.")
@@ -42,6 +57,8 @@ func (c *checker) VisitStruct(decl *ast.Struct) {
 }
 
 func (c *checker) VisitImpl(decl *ast.Impl) {
+	c.checkAttributes(decl.Attributes, types.ImplTarget)
+
 	if decl.Type_ != nil {
 		c.pushScope()
 		c.addVariable(scanner.Token{Kind: scanner.Identifier, Lexeme: "this"}, decl.Type_)
@@ -56,6 +73,7 @@ func (c *checker) VisitImpl(decl *ast.Impl) {
 
 func (c *checker) VisitEnum(decl *ast.Enum) {
 	decl.AcceptChildren(c)
+	c.checkAttributes(decl.Attributes, types.EnumTarget)
 
 	// Check type
 	if decl.Type != nil {
@@ -76,25 +94,31 @@ func (c *checker) VisitEnum(decl *ast.Enum) {
 
 func (c *checker) VisitFunc(decl *ast.Func) {
 	// Check attributes
+	c.checkAttributes(decl.Attributes, types.FuncTarget)
+
 	for i, attribute := range decl.Attributes {
-		switch attribute := attribute.(type) {
+		switch value := attribute.Value.(type) {
 		case types.ExternAttribute:
-			if attribute.Name == "" {
-				decl.Attributes[i] = types.ExternAttribute{Name: decl.Name.Lexeme}
+			if value.Name == "" {
+				decl.Attributes[i].Value = types.ExternAttribute{Name: decl.Name.Lexeme}
 			}
 
 		case types.IntrinsicAttribute:
-			if attribute.Name == "" {
-				decl.Attributes[i] = types.IntrinsicAttribute{Name: decl.Name.Lexeme}
+			if value.Name == "" {
+				decl.Attributes[i].Value = types.IntrinsicAttribute{Name: decl.Name.Lexeme}
 			}
 
-		case types.InlineAttribute:
-
-		default:
-			c.errorToken(decl.Name, "Invalid attribute for a function.")
+		case types.ExportAttribute:
+			if value.Name == "" {
+				decl.Attributes[i].Value = types.ExportAttribute{Name: decl.Name.Lexeme}
+			}
 		}
 	}
 
+	for _, param := range decl.Params {
+		c.checkAttributes(param.Attributes, types.ParamTarget)
+	}
+
 	// Check flags
 	_, isImpl := decl.Parent().(*ast.Impl)
 
@@ -111,6 +135,25 @@ func (c *checker) VisitFunc(decl *ast.Func) {
 		c.errorToken(decl.Name, "Non static methods can't be intrinsics.")
 	}
 
+	var export types.ExportAttribute
+	isExport := decl.GetAttribute(&export)
+
+	if isExport {
+		c.checkExport(decl, isImpl, isExtern || isIntrinsic)
+	}
+
+	var test types.TestAttribute
+
+	if decl.GetAttribute(&test) {
+		c.checkTest(decl, "Tests", isImpl, isExtern || isIntrinsic)
+	}
+
+	var bench types.BenchAttribute
+
+	if decl.GetAttribute(&bench) {
+		c.checkTest(decl, "Benchmarks", isImpl, isExtern || isIntrinsic)
+	}
+
 	if decl.IsVariadic() && !isExtern {
 		c.errorToken(decl.Name, "Only extern functions can be variadic.")
 	}
@@ -125,6 +168,14 @@ func (c *checker) VisitFunc(decl *ast.Func) {
 	c.function = decl
 	c.pushScope()
 
+	// Const methods see 'this' as immutable
+	if decl.IsConst() {
+		this := c.addVariable(scanner.Token{Kind: scanner.Identifier, Lexeme: "this"}, decl.Method())
+
+		this.immutable = true
+		this.used = true
+	}
+
 	// Params
 	for _, param := range decl.Params {
 		if c.hasVariableInScope(param.Name) {
@@ -166,6 +217,129 @@ func (c *checker) VisitFunc(decl *ast.Func) {
 	}
 }
 
+func (c *checker) VisitStaticAssert(decl *ast.StaticAssert) {
+	decl.AcceptChildren(c)
+	c.checkAttributes(decl.Attributes, types.StaticAssertTarget)
+	c.checkStaticAssert(decl.Condition, decl.Message)
+}
+
+func (c *checker) checkStaticAssert(condition ast.Expr, message scanner.Token) {
+	if condition == nil || condition.Result().Kind == ast.InvalidResultKind {
+		return // Do not cascade errors
+	}
+
+	// Check condition value
+	if condition.Result().Kind != ast.ValueResultKind {
+		c.errorRange(condition.Range(), "Invalid value.")
+		return
+	}
+
+	if !types.IsPrimitive(condition.Result().Type, types.Bool) {
+		c.errorRange(condition.Range(), "Condition needs to be of type 'bool' but got '%s'.", condition.Result().Type)
+		return
+	}
+
+	// Evaluate condition
+	value := c.evaluate(condition)
+
+	if value.Kind() != constant.Bool {
+		c.errorRange(condition.Range(), "Static assertion condition needs to be a constant expression.")
+	} else if !constant.BoolVal(value) {
+		text, _ := scanner.ParseString(message)
+		c.errorRange(condition.Range(), "Static assertion failed: %s", text)
+	}
+}
+
+// checkAttributes resolves attributes declared by the project and reports attributes not allowed on the target.
+func (c *checker) checkAttributes(attributes []ast.Attribute, target types.AttributeTarget) {
+	for i := range attributes {
+		attribute := &attributes[i]
+		schema := types.GetBuiltinAttribute(attribute.Name.Lexeme)
+
+		if schema == nil {
+			// Project attribute
+			schema = c.resolver.GetAttribute(attribute.Name.Lexeme)
+			attribute.Value = nil
+
+			if schema == nil {
+				c.errorToken(attribute.Name, "Unknown attribute with name '%s'.", attribute.Name)
+				continue
+			}
+
+			if !attribute.Resolve(schema, c.errorToken) {
+				continue
+			}
+		}
+
+		// Check target
+		if schema.Targets&target == 0 {
+			c.errorToken(attribute.Name, "Attribute '%s' can only be used on: %s.", attribute.Name, schema.Targets)
+		}
+	}
+}
+
+func (c *checker) checkExport(decl *ast.Func, isImpl, noBody bool) {
+	if isImpl && !decl.IsStatic() {
+		c.errorToken(decl.Name, "Non static methods can't be exported.")
+	}
+
+	if noBody {
+		c.errorToken(decl.Name, "Only functions with a body can be exported.")
+	}
+
+	// C can't call functions taking or returning structs and arrays by value with the layout LLVM uses for them
+	for _, param := range decl.Params {
+		if param.Type != nil && !isExportableType(param.Type) {
+			c.errorRange(param.Type.Range(), "Exported functions can only use primitive, pointer, enum and function types but got '%s'.", param.Type)
+		}
+	}
+
+	if decl.Returns != nil && !isExportableType(decl.Returns) {
+		c.errorToken(decl.Name, "Exported functions can only use primitive, pointer, enum and function types but got '%s'.", decl.Returns)
+	}
+}
+
+func (c *checker) checkTest(decl *ast.Func, kind string, isImpl, noBody bool) {
+	if isImpl {
+		c.errorToken(decl.Name, "%s can't be methods.", kind)
+	}
+
+	if noBody {
+		c.errorToken(decl.Name, "%s need a body.", kind)
+	}
+
+	if len(decl.Params) > 0 {
+		c.errorToken(decl.Name, "%s can't take parameters.", kind)
+	}
+
+	if decl.Returns != nil && !types.IsPrimitive(decl.Returns, types.Void) {
+		c.errorToken(decl.Name, "%s can't return a value.", kind)
+	}
+}
+
+func isExportableType(type_ types.Type) bool {
+	switch type_ := type_.(type) {
+	case *types.PrimitiveType, *types.PointerType, *ast.Enum:
+		return true
+
+	case *ast.Func:
+		if type_.IsBound() {
+			return false
+		}
+
+		for _, param := range type_.Params {
+			if !isExportableType(param.Type) {
+				return false
+			}
+		}
+
+		return isExportableType(type_.Returns)
+
+	default:
+		return false
+	}
+}
+
 func (c *checker) checkIntrinsic(decl *ast.Func, intrinsic types.IntrinsicAttribute) {
 	valid := false
 
@@ -0,0 +1,154 @@
+package checker
+
+import (
+	"fireball/core"
+	"fireball/core/ast"
+	"fireball/core/scanner"
+	"fireball/core/types"
+)
+
+// promoted is a field or method of an embedded struct, path contains the embedded fields leading to it.
+type promoted struct {
+	path []*ast.Field
+
+	field    *ast.Field
+	function *ast.Func
+	filePath string
+}
+
+// promote searches the structs embedded in s for a field or method with the given name. Shallower embeddings hide
+// deeper ones, if multiple embedded structs at the same depth provide the name an error is reported and the second
+// return value is true.
+func (c *checker) promote(s *ast.Struct, name scanner.Token, method bool) (*promoted, bool) {
+	for _, level := range s.GetEmbedded() {
+		var found *promoted
+		var provider *ast.Field
+
+		for _, embedding := range level {
+			result := promoted{path: embedding.Path}
+
+			if method {
+				result.function, result.filePath = c.resolver.GetMethod(embedding.Struct, name.Lexeme, false)
+			} else {
+				_, result.field = embedding.Struct.GetField(name.Lexeme)
+			}
+
+			if result.function == nil && result.field == nil {
+				continue
+			}
+
+			last := embedding.Path[len(embedding.Path)-1]
+
+			if found != nil {
+				c.errorToken(name, "Ambiguous member '%s', it is provided by both '%s' and '%s'.", name, provider.Name, last.Name)
+				return nil, true
+			}
+
+			found = &result
+			provider = last
+		}
+
+		if found != nil {
+			return found, false
+		}
+	}
+
+	return nil, false
+}
+
+// embed replaces the value of the member with implicit member accesses of the embedded fields in the path.
+func (c *checker) embed(expr *ast.Member, path []*ast.Field) {
+	value := expr.Value
+	value.SetParent(nil)
+
+	for _, field := range path {
+		c.checkEmbeddedVisible(field, expr.Name)
+
+		name := expr.Name
+		name.Lexeme = field.Name.Lexeme
+
+		member := &ast.Member{
+			Value:    value,
+			Name:     name,
+			Implicit: true,
+		}
+
+		member.SetRangeNode(value, value)
+		member.SetChildrenParent()
+		member.Result().SetValue(field.Type, elementFlags(value.Result()))
+
+		value = member
+	}
+
+	expr.Value = value
+	expr.SetChildrenParent()
+}
+
+// convertEmbedded wraps a pointer to a struct which is assigned to a pointer to one of its embedded structs in an
+// implicit cast. Returns the expression which replaces the original one.
+func (c *checker) convertEmbedded(expr ast.Expr, type_ types.Type) ast.Expr {
+	if expr == nil || expr.Result().Kind != ast.ValueResultKind {
+		return expr
+	}
+
+	from, ok := embeddedPointee(expr.Result().Type)
+	if !ok {
+		return expr
+	}
+
+	to, ok := embeddedPointee(type_)
+	if !ok || from.Name.Lexeme == to.Name.Lexeme || !from.Embeds(to) {
+		return expr
+	}
+
+	parent := expr.Parent()
+	expr.SetParent(nil)
+
+	cast := &ast.Cast{
+		Token_:   expr.Token(),
+		Target:   type_.WithRange(core.Range{}),
+		Expr:     expr,
+		Implicit: true,
+	}
+
+	cast.SetRangeNode(expr, expr)
+	cast.SetChildrenParent()
+	cast.SetParent(parent)
+	cast.Result().SetValue(type_, 0)
+
+	return cast
+}
+
+// convertArrayInitializer gives an array initializer the array type expected by its context if all values can be
+// assigned to the element type. Arrays can't be converted as a whole, so values pointing to structs embedding the
+// element type are converted one by one.
+func (c *checker) convertArrayInitializer(expr *ast.ArrayInitializer, type_ types.Type) {
+	array, ok := type_.(*types.ArrayType)
+
+	if !ok || expr.Result().Kind != ast.ValueResultKind || int(array.Count) != len(expr.Values) || expr.Result().Type.Equals(array) {
+		return
+	}
+
+	for _, value := range expr.Values {
+		c.convertUntyped(value, array.Base)
+
+		if !value.Result().Type.CanAssignTo(array.Base) {
+			return
+		}
+	}
+
+	for i, value := range expr.Values {
+		expr.Values[i] = c.convertEmbedded(value, array.Base)
+	}
+
+	expr.Result().SetValue(array.WithRange(core.Range{}), 0)
+}
+
+func embeddedPointee(type_ types.Type) (*ast.Struct, bool) {
+	if pointer, ok := type_.(*types.PointerType); ok {
+		struct_, ok := pointer.Pointee.(*ast.Struct)
+		return struct_, ok
+	}
+
+	return nil, false
+}
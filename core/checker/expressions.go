@@ -6,22 +6,23 @@ import (
 	"fireball/core/scanner"
 	"fireball/core/types"
 	"fireball/core/utils"
+	"go/constant"
+	"go/token"
 	"log"
-	"strconv"
-	"strings"
 )
 
 func (c *checker) VisitGroup(expr *ast.Group) {
 	expr.AcceptChildren(c)
 
 	*expr.Result() = *expr.Expr.Result()
-	expr.Result().Flags = 0
+	expr.Result().Flags &= ast.UntypedFlag
 }
 
 func (c *checker) VisitLiteral(expr *ast.Literal) {
 	expr.AcceptChildren(c)
 
 	var kind types.PrimitiveKind
+	var flags ast.ExprResultFlags
 	pointer := false
 
 	switch expr.Value.Kind {
@@ -32,65 +33,73 @@ func (c *checker) VisitLiteral(expr *ast.Literal) {
 	case scanner.True, scanner.False:
 		kind = types.Bool
 
-	case scanner.Number:
-		raw := expr.Value.Lexeme
-		last := raw[len(raw)-1]
+	case scanner.Number, scanner.Hex, scanner.Binary, scanner.Octal:
+		value, suffix := scanner.ParseNumber(expr.Value)
 
-		if last == 'f' || last == 'F' {
-			_, err := strconv.ParseFloat(raw[:len(raw)-1], 32)
-			if err != nil {
-				c.errorToken(expr.Value, "Invalid float.")
-				expr.Result().SetInvalid()
+		if value.Kind() == constant.Unknown {
+			c.errorToken(expr.Value, "Invalid number.")
+			expr.Result().SetInvalid()
 
-				return
+			return
+		}
+
+		if suffix == "" {
+			// Untyped constant
+			switch {
+			case value.Kind() == constant.Float:
+				kind = types.F64
+			case expr.Value.Kind == scanner.Number:
+				kind = types.I32
+			default:
+				kind = types.U32
 			}
 
-			kind = types.F32
-		} else if strings.ContainsRune(raw, '.') {
-			_, err := strconv.ParseFloat(raw, 64)
-			if err != nil {
-				c.errorToken(expr.Value, "Invalid double.")
+			flags = ast.UntypedFlag
+		} else {
+			// Typed by the suffix, the value is negated first when the literal is negated
+			var ok bool
+			kind, ok = getSuffixType(suffix)
+
+			if !ok {
+				c.errorToken(expr.Value, "Invalid number suffix '%s'.", suffix)
 				expr.Result().SetInvalid()
 
 				return
 			}
 
-			kind = types.F64
-		} else {
-			kind = types.I32
+			if unary, ok := expr.Parent().(*ast.Unary); ok && unary.Prefix && unary.Op.Kind == scanner.Minus {
+				value = constant.UnaryOp(token.SUB, value, 0)
+			}
+
+			if _, ok := c.representable(expr.Range(), value, types.Primitive(kind, core.Range{})); !ok {
+				expr.Result().SetInvalid()
+				return
+			}
 		}
 
-	case scanner.Hex:
-		_, err := strconv.ParseUint(expr.Value.Lexeme[2:], 16, 64)
-		if err != nil {
-			c.errorToken(expr.Value, "Invalid hex integer.")
+	case scanner.Character:
+		if _, err := scanner.ParseCharacter(expr.Value); err != nil {
+			c.errorToken(expr.Value, "Invalid character: %s.", err)
 			expr.Result().SetInvalid()
 
 			return
 		}
 
-		kind = types.U32
+		kind = types.U8
 
-	case scanner.Binary:
-		_, err := strconv.ParseUint(expr.Value.Lexeme[2:], 2, 64)
-		if err != nil {
-			c.errorToken(expr.Value, "Invalid binary integer.")
+	case scanner.String:
+		if _, err := scanner.ParseString(expr.Value); err != nil {
+			c.errorToken(expr.Value, "Invalid string: %s.", err)
 			expr.Result().SetInvalid()
 
 			return
 		}
 
-		kind = types.U32
-
-	case scanner.Character:
-		kind = types.U8
-
-	case scanner.String:
 		kind = types.U8
 		pointer = true
 	}
 
-	expr.Result().SetValue(types.Primitive(kind, core.Range{}), 0)
+	expr.Result().SetValue(types.Primitive(kind, core.Range{}), flags)
 
 	if pointer {
 		expr.Result().SetValue(types.Pointer(expr.Result().Type, core.Range{}), 0)
@@ -121,7 +130,7 @@ func (c *checker) VisitStructInitializer(expr *ast.StructInitializer) {
 	// Check fields
 	assignedFields := utils.NewSet[string]()
 
-	for _, initField := range expr.Fields {
+	for i, initField := range expr.Fields {
 		// Check name collision
 		if !assignedFields.Add(initField.Name.Lexeme) {
 			c.errorToken(initField.Name, "Field with the name '%s' was already assigned.", initField.Name)
@@ -134,6 +143,8 @@ func (c *checker) VisitStructInitializer(expr *ast.StructInitializer) {
 			continue
 		}
 
+		c.checkFieldVisible(field, initField.Name)
+
 		// Check value result
 		if initField.Value.Result().Kind == ast.InvalidResultKind {
 			continue // Do not cascade errors
@@ -144,9 +155,13 @@ func (c *checker) VisitStructInitializer(expr *ast.StructInitializer) {
 			continue
 		}
 
+		c.convertUntyped(initField.Value, field.Type)
+
 		if !initField.Value.Result().Type.CanAssignTo(field.Type) {
 			c.errorRange(initField.Value.Range(), "Expected a '%s' but got '%s'.", field.Type, initField.Value.Result().Type)
 		}
+
+		expr.Fields[i].Value = c.convertEmbedded(initField.Value, field.Type)
 	}
 
 	// Check malloc
@@ -166,11 +181,18 @@ func (c *checker) VisitArrayInitializer(expr *ast.ArrayInitializer) {
 		return
 	}
 
-	// Check values
+	// Check values, the first typed value decides the type of untyped constants
 	ok := true
 	var type_ types.Type
 
 	for _, value := range expr.Values {
+		if value.Result().Kind == ast.ValueResultKind && !value.Result().IsUntyped() {
+			type_ = value.Result().Type
+			break
+		}
+	}
+
+	for i, value := range expr.Values {
 		if value.Result().Kind == ast.InvalidResultKind {
 			ok = false
 			continue
@@ -184,12 +206,17 @@ func (c *checker) VisitArrayInitializer(expr *ast.ArrayInitializer) {
 		}
 
 		if type_ == nil {
+			c.defaultUntyped(value)
 			type_ = value.Result().Type
 		} else {
+			c.convertUntyped(value, type_)
+
 			if !value.Result().Type.CanAssignTo(type_) {
 				c.errorRange(value.Range(), "Expected a '%s' but got '%s'.", type_, value.Result().Type)
 				ok = false
 			}
+
+			expr.Values[i] = c.convertEmbedded(value, type_)
 		}
 	}
 
@@ -258,8 +285,24 @@ func (c *checker) VisitUnary(expr *ast.Unary) {
 			}
 
 			if v, ok := result.Type.(*types.PrimitiveType); ok {
+				// Untyped constants stay untyped, unsigned ones become signed
+				if result.IsUntyped() {
+					if types.IsUnsigned(v.Kind) {
+						expr.Result().SetValue(types.Primitive(types.I32, core.Range{}), ast.UntypedFlag)
+					} else {
+						expr.Result().SetValue(result.Type, ast.UntypedFlag)
+					}
+
+					return
+				}
+
 				if types.IsFloating(v.Kind) || types.IsSigned(v.Kind) {
 					expr.Result().SetValue(result.Type, 0)
+
+					if value := c.evaluate(expr.Value); value.Kind() == constant.Int {
+						c.checkConstantOverflow(expr, constant.UnaryOp(token.SUB, value, 0), v)
+					}
+
 					return
 				}
 			}
@@ -269,7 +312,12 @@ func (c *checker) VisitUnary(expr *ast.Unary) {
 
 		case scanner.Ampersand:
 			if result.IsAddressable() {
-				expr.Result().SetValue(types.Pointer(result.Type, core.Range{}), 0)
+				// Immutable values can only be read through the pointer
+				if result.IsImmutable() {
+					expr.Result().SetValue(types.ConstPointer(result.Type, core.Range{}), 0)
+				} else {
+					expr.Result().SetValue(types.Pointer(result.Type, core.Range{}), 0)
+				}
 			} else {
 				c.errorRange(expr.Value.Range(), "Cannot take address of this expression.")
 				expr.Result().SetInvalid()
@@ -284,7 +332,11 @@ func (c *checker) VisitUnary(expr *ast.Unary) {
 			}
 
 			if p, ok := result.Type.(*types.PointerType); ok {
-				expr.Result().SetValue(p.Pointee, ast.AssignableFlag)
+				if p.Const {
+					expr.Result().SetValue(p.Pointee, ast.ImmutableFlag)
+				} else {
+					expr.Result().SetValue(p.Pointee, ast.AssignableFlag)
+				}
 			} else {
 				c.errorRange(expr.Value.Range(), "Can only dereference pointer types, not '%s'.", result.Type)
 				expr.Result().SetInvalid()
@@ -298,6 +350,13 @@ func (c *checker) VisitUnary(expr *ast.Unary) {
 				return
 			}
 
+			if result.IsImmutable() {
+				c.errorImmutable(expr.Value)
+				expr.Result().SetInvalid()
+
+				return
+			}
+
 			if type_, ok := result.Type.(*types.PrimitiveType); !ok || (!types.IsInteger(type_.Kind) && !types.IsFloating(type_.Kind)) {
 				c.errorRange(expr.Value.Range(), "Cannot increment or decrement '%s'.", result.Type)
 				expr.Result().SetInvalid()
@@ -309,14 +368,13 @@ func (c *checker) VisitUnary(expr *ast.Unary) {
 
 		case scanner.FuncPtr:
 			if result.Kind == ast.FunctionResultKind {
-				if result.Function.Method() != nil {
-					c.errorRange(expr.Value.Range(), "Cannot take address of a non-static method.")
-					expr.Result().SetInvalid()
-
-					return
+				if member, ok := expr.Value.(*ast.Member); ok && result.Function.Method() != nil {
+					// Method reference, bound to the instance unless taken on the struct itself
+					bound := member.Value.Result().Kind != ast.TypeResultKind
+					expr.Result().SetValue(result.Function.Reference(bound), 0)
+				} else {
+					expr.Result().SetValue(result.Function, 0)
 				}
-
-				expr.Result().SetValue(expr.Value.Result().Function, 0)
 			} else {
 				c.errorRange(expr.Value.Range(), "Cannot take address of this function.")
 				expr.Result().SetInvalid()
@@ -336,6 +394,13 @@ func (c *checker) VisitUnary(expr *ast.Unary) {
 				return
 			}
 
+			if result.IsImmutable() {
+				c.errorImmutable(expr.Value)
+				expr.Result().SetInvalid()
+
+				return
+			}
+
 			if type_, ok := result.Type.(*types.PrimitiveType); !ok || (!types.IsInteger(type_.Kind) && !types.IsFloating(type_.Kind)) {
 				c.errorRange(expr.Value.Range(), "Cannot increment or decrement '%s'.", result.Type)
 				expr.Result().SetInvalid()
@@ -376,16 +441,41 @@ func (c *checker) VisitBinary(expr *ast.Binary) {
 		return
 	}
 
+	// Untyped constants take the type of the other operand
+	if expr.Left.Result().IsUntyped() && expr.Right.Result().IsUntyped() {
+		if c.checkUntypedBinary(expr) {
+			return
+		}
+	} else {
+		c.convertUntyped(expr.Left, expr.Right.Result().Type)
+		c.convertUntyped(expr.Right, expr.Left.Result().Type)
+	}
+
 	leftType := expr.Left.Result().Type
 	rightType := expr.Right.Result().Type
 
 	// Check based on the operator
-	if scanner.IsArithmetic(expr.Op.Kind) {
+	if scanner.IsWrapping(expr.Op.Kind) {
+		// Wrapping arithmetic
+		if left, ok := leftType.(*types.PrimitiveType); ok {
+			if right, ok := rightType.(*types.PrimitiveType); ok {
+				if types.IsInteger(left.Kind) && left.Equals(right) {
+					expr.Result().SetValue(leftType, 0)
+					return
+				}
+			}
+		}
+
+		c.errorRange(expr.Range(), "Expected two equal integer types.")
+		expr.Result().SetInvalid()
+	} else if scanner.IsArithmetic(expr.Op.Kind) {
 		// Arithmetic
 		if left, ok := leftType.(*types.PrimitiveType); ok {
 			if right, ok := rightType.(*types.PrimitiveType); ok {
 				if types.IsNumber(left.Kind) && types.IsNumber(right.Kind) && left.Equals(right) {
 					expr.Result().SetValue(leftType, 0)
+					c.checkConstantOverflow(expr, evaluateBinary(expr.Op.Kind, c.evaluate(expr.Left), c.evaluate(expr.Right)), left)
+
 					return
 				}
 			}
@@ -502,12 +592,117 @@ func (c *checker) VisitLogical(expr *ast.Logical) {
 	expr.Result().SetValue(types.Primitive(types.Bool, core.Range{}), 0)
 }
 
+func (c *checker) VisitIfExpr(expr *ast.IfExpr) {
+	expr.AcceptChildren(c)
+
+	// Check condition value
+	valid := true
+
+	if expr.Condition.Result().Kind == ast.InvalidResultKind {
+		valid = false
+	} else if expr.Condition.Result().Kind != ast.ValueResultKind {
+		c.errorRange(expr.Condition.Range(), "Invalid value.")
+		valid = false
+	} else if !types.IsPrimitive(expr.Condition.Result().Type, types.Bool) {
+		c.errorRange(expr.Condition.Range(), "Condition needs to be of type 'bool' but got '%s'.", expr.Condition.Result().Type)
+		valid = false
+	}
+
+	// Check branch values
+	for _, branch := range []ast.Expr{expr.Then, expr.Else} {
+		if branch.Result().Kind == ast.InvalidResultKind {
+			valid = false
+		} else if branch.Result().Kind != ast.ValueResultKind {
+			c.errorRange(branch.Range(), "Invalid value.")
+			valid = false
+		}
+	}
+
+	if !valid {
+		expr.Result().SetInvalid()
+		return
+	}
+
+	// Branches leaving the function or loop early have no value, the expression has the value of the other one
+	if thenDiverges, elseDiverges := diverges(expr.Then), diverges(expr.Else); thenDiverges != elseDiverges {
+		value := expr.Else
+		if elseDiverges {
+			value = expr.Then
+		}
+
+		expr.Result().SetValue(value.Result().Type, value.Result().Flags&ast.UntypedFlag)
+		return
+	}
+
+	// Untyped constants stay untyped until the context converts them
+	if expr.Then.Result().IsUntyped() && expr.Else.Result().IsUntyped() {
+		expr.Result().SetValue(commonUntyped(expr.Then.Result().Type, expr.Else.Result().Type), ast.UntypedFlag)
+		return
+	}
+
+	c.convertUntyped(expr.Then, expr.Else.Result().Type)
+	c.convertUntyped(expr.Else, expr.Then.Result().Type)
+
+	// Unify branch types
+	thenType := expr.Then.Result().Type
+	elseType := expr.Else.Result().Type
+
+	var type_ types.Type
+
+	switch {
+	case thenType.Equals(elseType), elseType.CanAssignTo(thenType):
+		type_ = thenType
+
+	case thenType.CanAssignTo(elseType):
+		type_ = elseType
+
+	default:
+		c.errorRange(expr.Range(), "Branches need to have the same type but got '%s' and '%s'.", thenType, elseType)
+		expr.Result().SetInvalid()
+
+		return
+	}
+
+	// Pointers to structs embedding the result type point to the embedded field
+	expr.Then = c.convertEmbedded(expr.Then, type_)
+	expr.Else = c.convertEmbedded(expr.Else, type_)
+
+	expr.Result().SetValue(type_, 0)
+}
+
+func (c *checker) VisitBlockExpr(expr *ast.BlockExpr) {
+	c.pushScope()
+	expr.AcceptChildren(c)
+	c.popScope()
+
+	// Blocks without a value are void
+	if expr.Value == nil {
+		expr.Result().SetValue(types.Primitive(types.Void, core.Range{}), 0)
+		return
+	}
+
+	if expr.Value.Result().Kind == ast.InvalidResultKind {
+		expr.Result().SetInvalid()
+		return
+	}
+
+	if expr.Value.Result().Kind != ast.ValueResultKind {
+		c.errorRange(expr.Value.Range(), "Invalid value.")
+		expr.Result().SetInvalid()
+
+		return
+	}
+
+	expr.Result().SetValue(expr.Value.Result().Type, expr.Value.Result().Flags&ast.UntypedFlag)
+}
+
 func (c *checker) VisitIdentifier(expr *ast.Identifier) {
 	expr.AcceptChildren(c)
 
 	// Function / function pointer
 	if parentWantsFunction(expr) {
-		if f, _ := c.resolver.GetFunction(expr.Identifier.Lexeme); f != nil {
+		if f, path := c.resolver.GetFunction(expr.Identifier.Lexeme); f != nil {
+			c.checkFunctionVisible(f, expr.Identifier, path)
 			expr.Result().SetFunction(f)
 			expr.Kind = ast.FunctionKind
 
@@ -516,7 +711,8 @@ func (c *checker) VisitIdentifier(expr *ast.Identifier) {
 	}
 
 	// Type
-	if t, _ := c.resolver.GetType(expr.Identifier.Lexeme); t != nil {
+	if t, path := c.resolver.GetType(expr.Identifier.Lexeme); t != nil {
+		c.checkTypeVisible(t, expr.Identifier, path)
 		expr.Result().SetType(t.WithRange(core.Range{}))
 
 		if _, ok := t.(*ast.Enum); ok {
@@ -534,7 +730,11 @@ func (c *checker) VisitIdentifier(expr *ast.Identifier) {
 	if variable := c.getVariable(expr.Identifier); variable != nil {
 		variable.used = true
 
-		expr.Result().SetValue(variable.type_, ast.AssignableFlag|ast.AddressableFlag)
+		if variable.immutable {
+			expr.Result().SetValue(variable.type_, ast.AddressableFlag|ast.ImmutableFlag)
+		} else {
+			expr.Result().SetValue(variable.type_, ast.AssignableFlag|ast.AddressableFlag)
+		}
 
 		if variable.param {
 			expr.Kind = ast.ParameterKind
@@ -560,7 +760,10 @@ func (c *checker) VisitAssignment(expr *ast.Assignment) {
 	// Check results
 	ok := true
 
-	if !expr.Assignee.Result().IsAssignable() {
+	if expr.Assignee.Result().IsImmutable() {
+		c.errorImmutable(expr.Assignee)
+		ok = false
+	} else if !expr.Assignee.Result().IsAssignable() {
 		c.errorRange(expr.Assignee.Range(), "Cannot assign to this value.")
 		ok = false
 	}
@@ -575,6 +778,8 @@ func (c *checker) VisitAssignment(expr *ast.Assignment) {
 		return
 	}
 
+	c.convertUntyped(expr.Value, expr.Assignee.Result().Type)
+
 	// Check type
 	if expr.Op.Kind == scanner.Equal {
 		// Equal
@@ -584,6 +789,8 @@ func (c *checker) VisitAssignment(expr *ast.Assignment) {
 
 			return
 		}
+
+		expr.Value = c.convertEmbedded(expr.Value, expr.Assignee.Result().Type)
 	} else {
 		if scanner.IsArithmetic(expr.Op.Kind) {
 			// Arithmetic
@@ -603,8 +810,8 @@ func (c *checker) VisitAssignment(expr *ast.Assignment) {
 
 				return
 			}
-		} else if scanner.IsBitwise(expr.Op.Kind) {
-			// Bitwise
+		} else if scanner.IsBitwise(expr.Op.Kind) || scanner.IsWrapping(expr.Op.Kind) {
+			// Bitwise or wrapping arithmetic
 			valid := false
 
 			if left, ok := expr.Assignee.Result().Type.(*types.PrimitiveType); ok {
@@ -644,6 +851,13 @@ func (c *checker) VisitCast(expr *ast.Cast) {
 		return
 	}
 
+	// Untyped constants are converted directly if they fit, otherwise they are cast from their default type
+	if target, ok := expr.Target.(*types.PrimitiveType); ok && expr.Expr.Result().IsUntyped() && types.IsNumber(target.Kind) {
+		if _, problem := fit(c.evaluate(expr.Expr), target); problem == "" {
+			c.convertUntyped(expr.Expr, target)
+		}
+	}
+
 	if types.IsPrimitive(expr.Expr.Result().Type, types.Void) || types.IsPrimitive(expr.Target, types.Void) {
 		// void
 		c.errorRange(expr.Range(), "Cannot cast to or from type 'void'.")
@@ -677,10 +891,36 @@ func (c *checker) VisitTypeCall(expr *ast.TypeCall) {
 	expr.Result().SetValue(types.Primitive(types.I32, core.Range{}), 0)
 }
 
+func (c *checker) VisitAssert(expr *ast.Assert) {
+	expr.AcceptChildren(c)
+
+	// Assertions are statements, they have no value
+	expr.Result().SetValue(types.Primitive(types.Void, core.Range{}), 0)
+
+	// Check condition value
+	if expr.Condition.Result().Kind == ast.InvalidResultKind {
+		return // Do not cascade errors
+	}
+
+	if expr.Condition.Result().Kind != ast.ValueResultKind {
+		c.errorRange(expr.Condition.Range(), "Invalid value.")
+	} else if !types.IsPrimitive(expr.Condition.Result().Type, types.Bool) {
+		c.errorRange(expr.Condition.Range(), "Condition needs to be of type 'bool' but got '%s'.", expr.Condition.Result().Type)
+	}
+
+	// Check message
+	if expr.Message.Kind == scanner.String {
+		if _, err := scanner.ParseString(expr.Message); err != nil {
+			c.errorToken(expr.Message, "Invalid string: %s.", err)
+		}
+	}
+}
+
 func (c *checker) VisitCall(expr *ast.Call) {
 	expr.AcceptChildren(c)
 
 	if expr.Callee.Result().Kind == ast.InvalidResultKind {
+		expr.Result().SetInvalid()
 		return // Do not cascade errors
 	}
 
@@ -733,10 +973,14 @@ func (c *checker) VisitCall(expr *ast.Call) {
 			continue // Do not cascade errors
 		}
 
+		c.convertUntyped(arg, param.Type)
+
 		if !arg.Result().Type.CanAssignTo(param.Type) {
 			c.errorRange(arg.Range(), "Argument with type '%s' cannot be assigned to a parameter with type '%s'.", arg.Result().Type, param.Type)
 			ok = false
 		}
+
+		expr.Args[i] = c.convertEmbedded(arg, param.Type)
 	}
 
 	if !ok {
@@ -802,7 +1046,7 @@ func (c *checker) VisitIndex(expr *ast.Index) {
 
 	// Set result
 	if ok {
-		expr.Result().SetValue(base, ast.AssignableFlag|ast.AddressableFlag)
+		expr.Result().SetValue(base, elementFlags(expr.Value.Result()))
 	} else {
 		expr.Result().SetInvalid()
 	}
@@ -822,7 +1066,12 @@ func (c *checker) VisitMember(expr *ast.Member) {
 			if v, ok := expr.Value.Result().Type.(*ast.Struct); ok {
 				// Check if parent expression wants a function
 				if parentWantsFunction(expr) {
-					function, _ := c.resolver.GetMethod(v, expr.Name.Lexeme, true)
+					function, path := c.resolver.GetMethod(v, expr.Name.Lexeme, true)
+
+					// References can also point to instance methods
+					if function == nil && isFunctionReference(expr) {
+						function, path = c.resolver.GetMethod(v, expr.Name.Lexeme, false)
+					}
 
 					if function == nil {
 						c.errorToken(expr.Name, "Struct '%s' does not contain static method with the name '%s'.", v, expr.Name)
@@ -831,6 +1080,8 @@ func (c *checker) VisitMember(expr *ast.Member) {
 						return
 					}
 
+					c.checkFunctionVisible(function, expr.Name, path)
+
 					expr.Result().SetFunction(function)
 					return
 				}
@@ -845,6 +1096,8 @@ func (c *checker) VisitMember(expr *ast.Member) {
 					return
 				}
 
+				c.checkFieldVisible(field, expr.Name)
+
 				expr.Result().SetValue(field.Type, ast.AssignableFlag|ast.AddressableFlag)
 				return
 			}
@@ -890,9 +1143,34 @@ func (c *checker) VisitMember(expr *ast.Member) {
 
 		// Check if parent expression wants a function
 		if parentWantsFunction(expr) {
-			function, _ := c.resolver.GetMethod(s, expr.Name.Lexeme, false)
+			function, path := c.resolver.GetMethod(s, expr.Name.Lexeme, false)
+
+			// Promoted method
+			if function == nil {
+				promoted, reported := c.promote(s, expr.Name, true)
+
+				if reported {
+					expr.Result().SetInvalid()
+					return
+				}
+
+				if promoted != nil {
+					c.embed(expr, promoted.path)
+					function, path = promoted.function, promoted.filePath
+				}
+			}
 
 			if function != nil {
+				c.checkFunctionVisible(function, expr.Name, path)
+
+				// Methods which are not const can modify 'this', so they can't be called on immutable values
+				if !function.IsConst() && elementFlags(expr.Value.Result())&ast.ImmutableFlag != 0 {
+					c.errorImmutableMethod(expr)
+					expr.Result().SetInvalid()
+
+					return
+				}
+
 				expr.Result().SetFunction(function)
 			} else {
 				c.errorToken(expr.Name, "Struct '%s' does not contain method '%s'.", s, expr.Name)
@@ -905,6 +1183,21 @@ func (c *checker) VisitMember(expr *ast.Member) {
 		// Check field
 		_, field := s.GetField(expr.Name.Lexeme)
 
+		// Promoted field
+		if field == nil {
+			promoted, reported := c.promote(s, expr.Name, false)
+
+			if reported {
+				expr.Result().SetInvalid()
+				return
+			}
+
+			if promoted != nil {
+				c.embed(expr, promoted.path)
+				field = promoted.field
+			}
+		}
+
 		if field == nil {
 			c.errorToken(expr.Name, "Struct '%s' does not contain field '%s'.", s, expr.Name)
 			expr.Result().SetInvalid()
@@ -912,7 +1205,9 @@ func (c *checker) VisitMember(expr *ast.Member) {
 			return
 		}
 
-		expr.Result().SetValue(field.Type, ast.AssignableFlag|ast.AddressableFlag)
+		c.checkFieldVisible(field, expr.Name)
+
+		expr.Result().SetValue(field.Type, elementFlags(expr.Value.Result()))
 		return
 	}
 
@@ -923,6 +1218,63 @@ func (c *checker) VisitMember(expr *ast.Member) {
 
 // Utils
 
+// diverges returns true if the expression always leaves the function or loop before producing a value.
+func diverges(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.BlockExpr:
+		if expr.Value != nil || len(expr.Stmts) == 0 {
+			return false
+		}
+
+		switch expr.Stmts[len(expr.Stmts)-1].(type) {
+		case *ast.Return, *ast.Break, *ast.Continue:
+			return true
+		}
+
+	case *ast.IfExpr:
+		return diverges(expr.Then) && diverges(expr.Else)
+
+	case *ast.Group:
+		return diverges(expr.Expr)
+	}
+
+	return false
+}
+
+// elementFlags returns the result flags of a field or an element reached through the value, they are immutable if the
+// value is immutable or a '*const' pointer.
+func elementFlags(value *ast.ExprResult) ast.ExprResultFlags {
+	immutable := value.IsImmutable()
+
+	if pointer, ok := value.Type.(*types.PointerType); ok {
+		immutable = pointer.Const
+	}
+
+	if immutable {
+		return ast.AddressableFlag | ast.ImmutableFlag
+	}
+
+	return ast.AssignableFlag | ast.AddressableFlag
+}
+
+// errorImmutable reports a write to an immutable value.
+func (c *checker) errorImmutable(expr ast.Expr) {
+	if identifier, ok := expr.(*ast.Identifier); ok {
+		c.errorRange(expr.Range(), "Cannot modify immutable variable '%s'.", identifier.Identifier)
+	} else {
+		c.errorRange(expr.Range(), "Cannot modify a value behind a '*const' pointer or an immutable variable.")
+	}
+}
+
+// errorImmutableMethod reports a call of a method which is not const on an immutable value.
+func (c *checker) errorImmutableMethod(expr *ast.Member) {
+	if identifier, ok := expr.Value.(*ast.Identifier); ok && expr.Value.Result().IsImmutable() {
+		c.errorRange(expr.Value.Range(), "Cannot call non const method '%s' on immutable variable '%s'.", expr.Name, identifier.Identifier)
+	} else {
+		c.errorRange(expr.Value.Range(), "Cannot call non const method '%s' on a value behind a '*const' pointer or an immutable variable.", expr.Name)
+	}
+}
+
 func parentWantsFunction(expr ast.Expr) bool {
 	switch parent := expr.Parent().(type) {
 	case *ast.Call:
@@ -936,6 +1288,14 @@ func parentWantsFunction(expr ast.Expr) bool {
 	}
 }
 
+func isFunctionReference(expr ast.Expr) bool {
+	if parent, ok := expr.Parent().(*ast.Unary); ok {
+		return parent.Op.Kind == scanner.FuncPtr
+	}
+
+	return false
+}
+
 func (c *checker) checkMalloc(expr ast.Expr) {
 	function, _ := c.resolver.GetFunction("malloc")
 
@@ -944,6 +1304,9 @@ func (c *checker) checkMalloc(expr ast.Expr) {
 		return
 	}
 
+	// Used implicitly by the language so it does not need to be public
+	c.usages.items.Add(function)
+
 	if len(function.Params) != 1 || !types.IsPrimitive(function.Params[0].Type, types.U64) {
 		c.errorRange(expr.Range(), "Malloc parameter needs to be a u64.")
 	}
@@ -21,6 +21,15 @@ func (r *resetter) AcceptDecl(decl ast.Decl) {
 }
 
 func (r *resetter) AcceptStmt(stmt ast.Stmt) {
+	// Remove implicit nodes inserted by the checker
+	switch stmt := stmt.(type) {
+	case *ast.Variable:
+		stmt.Initializer = unwrap(stmt.Initializer, stmt)
+
+	case *ast.Return:
+		stmt.Expr = unwrap(stmt.Expr, stmt)
+	}
+
 	stmt.AcceptChildren(r)
 
 	switch stmt := stmt.(type) {
@@ -32,7 +41,57 @@ func (r *resetter) AcceptStmt(stmt ast.Stmt) {
 }
 
 func (r *resetter) AcceptExpr(expr ast.Expr) {
+	// Remove implicit nodes inserted by the checker
+	switch expr := expr.(type) {
+	case *ast.Member:
+		expr.Value = unwrap(expr.Value, expr)
+
+	case *ast.Assignment:
+		expr.Value = unwrap(expr.Value, expr)
+
+	case *ast.Call:
+		for i, arg := range expr.Args {
+			expr.Args[i] = unwrap(arg, expr)
+		}
+
+	case *ast.StructInitializer:
+		for i, field := range expr.Fields {
+			expr.Fields[i].Value = unwrap(field.Value, expr)
+		}
+
+	case *ast.ArrayInitializer:
+		for i, value := range expr.Values {
+			expr.Values[i] = unwrap(value, expr)
+		}
+
+	case *ast.IfExpr:
+		expr.Then = unwrap(expr.Then, expr)
+		expr.Else = unwrap(expr.Else, expr)
+	}
+
 	expr.AcceptChildren(r)
 
 	expr.Result().SetInvalid()
 }
+
+// unwrap returns the expression wrapped by implicit members and casts and moves it to the parent.
+func unwrap(expr ast.Expr, parent ast.Node) ast.Expr {
+	unwrapped := expr
+
+	for {
+		if member, ok := unwrapped.(*ast.Member); ok && member.Implicit {
+			unwrapped = member.Value
+		} else if cast, ok := unwrapped.(*ast.Cast); ok && cast.Implicit {
+			unwrapped = cast.Expr
+		} else {
+			break
+		}
+	}
+
+	if unwrapped != expr {
+		unwrapped.SetParent(nil)
+		unwrapped.SetParent(parent)
+	}
+
+	return unwrapped
+}
@@ -35,12 +35,17 @@ func (c *checker) VisitVariable(stmt *ast.Variable) {
 					c.errorToken(stmt.Name, "Variable with no initializer needs to have an explicit type.")
 					valueOk = false
 				} else {
+					c.defaultUntyped(stmt.Initializer)
 					stmt.Type = stmt.Initializer.Result().Type
 				}
 			} else {
+				c.convertUntyped(stmt.Initializer, stmt.Type)
+
 				if stmt.Initializer != nil && !stmt.Initializer.Result().Type.CanAssignTo(stmt.Type) {
 					c.errorRange(stmt.Initializer.Range(), "Initializer with type '%s' cannot be assigned to a variable with type '%s'.", stmt.Initializer.Result().Type, stmt.Type)
 				}
+
+				stmt.Initializer = c.convertEmbedded(stmt.Initializer, stmt.Type)
 			}
 		}
 	}
@@ -50,11 +55,7 @@ func (c *checker) VisitVariable(stmt *ast.Variable) {
 	}
 
 	// Check name collision
-	if c.hasVariableInScope(stmt.Name) {
-		c.errorToken(stmt.Name, "Variable with the name '%s' already exists in the current scope.", stmt.Name)
-	} else {
-		c.addVariable(stmt.Name, stmt.Type)
-	}
+	c.declareVariable(stmt)
 
 	// Check void type
 	if valueOk && types.IsPrimitive(stmt.Type, types.Void) {
@@ -62,6 +63,95 @@ func (c *checker) VisitVariable(stmt *ast.Variable) {
 	}
 }
 
+func (c *checker) VisitDestructure(stmt *ast.Destructure) {
+	c.AcceptExpr(stmt.Initializer)
+
+	// Check initializer value
+	valueOk := false
+	result := stmt.Initializer.Result()
+
+	if result.Kind != ast.InvalidResultKind {
+		if result.Kind != ast.ValueResultKind {
+			c.errorRange(stmt.Initializer.Range(), "Invalid value.")
+		} else if stmt.Array {
+			valueOk = c.checkArrayPattern(stmt, result.Type)
+		} else {
+			valueOk = c.checkStructPattern(stmt, result.Type)
+		}
+	}
+
+	// Declare bindings
+	for _, binding := range stmt.Bindings {
+		if binding, ok := binding.(*ast.Variable); ok {
+			if !valueOk {
+				binding.Type = types.Primitive(types.Void, core.Range{})
+			}
+
+			c.declareVariable(binding)
+		}
+	}
+}
+
+func (c *checker) checkArrayPattern(stmt *ast.Destructure, type_ types.Type) bool {
+	array, ok := type_.(*types.ArrayType)
+	if !ok {
+		c.errorRange(stmt.Initializer.Range(), "Cannot destructure type '%s' as an array.", type_)
+		return false
+	}
+
+	if len(stmt.Bindings) != int(array.Count) {
+		c.errorRange(stmt.Initializer.Range(), "Pattern has %d bindings but the array has %d elements.", len(stmt.Bindings), array.Count)
+		return false
+	}
+
+	for _, binding := range stmt.Bindings {
+		if binding, ok := binding.(*ast.Variable); ok {
+			binding.Type = array.Base
+		}
+	}
+
+	return true
+}
+
+func (c *checker) checkStructPattern(stmt *ast.Destructure, type_ types.Type) bool {
+	s, ok := type_.(*ast.Struct)
+	if !ok {
+		c.errorRange(stmt.Initializer.Range(), "Cannot destructure type '%s' as a struct.", type_)
+		return false
+	}
+
+	if stmt.Type != nil && !type_.Equals(stmt.Type) {
+		c.errorRange(stmt.Initializer.Range(), "Cannot destructure type '%s' as '%s'.", type_, stmt.Type)
+		return false
+	}
+
+	ok = true
+
+	for _, binding := range stmt.Bindings {
+		if binding, isVariable := binding.(*ast.Variable); isVariable {
+			_, field := s.GetField(binding.Name.Lexeme)
+
+			if field == nil {
+				c.errorToken(binding.Name, "Struct '%s' does not contain field '%s'.", s, binding.Name)
+				ok = false
+			} else {
+				c.checkFieldVisible(field, binding.Name)
+				binding.Type = field.Type
+			}
+		}
+	}
+
+	return ok
+}
+
+func (c *checker) declareVariable(stmt *ast.Variable) {
+	if c.hasVariableInScope(stmt.Name) {
+		c.errorToken(stmt.Name, "Variable with the name '%s' already exists in the current scope.", stmt.Name)
+	} else {
+		c.addVariable(stmt.Name, stmt.Type).immutable = stmt.Immutable
+	}
+}
+
 func (c *checker) VisitIf(stmt *ast.If) {
 	stmt.AcceptChildren(c)
 
@@ -105,11 +195,17 @@ func (c *checker) VisitReturn(stmt *ast.Return) {
 	var range_ core.Range
 
 	if stmt.Expr != nil {
+		if stmt.Expr.Result().Kind == ast.InvalidResultKind {
+			return // Do not cascade errors
+		}
+
 		if stmt.Expr.Result().Kind != ast.ValueResultKind {
 			c.errorRange(stmt.Expr.Range(), "Invalid value.")
 			return
 		}
 
+		c.convertUntyped(stmt.Expr, c.function.Returns)
+
 		type_ = stmt.Expr.Result().Type
 		range_ = stmt.Expr.Range()
 	} else {
@@ -120,6 +216,8 @@ func (c *checker) VisitReturn(stmt *ast.Return) {
 	if !type_.CanAssignTo(c.function.Returns) {
 		c.errorRange(range_, "Cannot return type '%s' from a function with return type '%s'.", type_, c.function.Returns)
 	}
+
+	stmt.Expr = c.convertEmbedded(stmt.Expr, c.function.Returns)
 }
 
 func (c *checker) VisitBreak(stmt *ast.Break) {
@@ -139,3 +237,8 @@ func (c *checker) VisitContinue(stmt *ast.Continue) {
 		c.errorToken(stmt.Token(), "A 'continue' statement needs to be inside a loop.")
 	}
 }
+
+func (c *checker) VisitStaticAssertStmt(stmt *ast.StaticAssertStmt) {
+	stmt.AcceptChildren(c)
+	c.checkStaticAssert(stmt.Condition, stmt.Message)
+}
@@ -0,0 +1,271 @@
+package checker
+
+import (
+	"fireball/core"
+	"fireball/core/ast"
+	"fireball/core/scanner"
+	"fireball/core/types"
+	"go/constant"
+	"go/token"
+	"math"
+)
+
+// Untyped constants are number literals without a suffix and arithmetic on them. They keep arbitrary precision and
+// their result type is only the default one (i32, u32 for hex and binary literals, f64) until the context converts
+// them to the type it expects. Converted constants are folded and emitted as a single value.
+
+// convertUntyped gives an untyped constant expression the specified type, array initializers are given the array type
+// too and other expressions are left untouched. Reports an error and returns false if the value cannot be represented
+// by the type.
+func (c *checker) convertUntyped(expr ast.Expr, type_ types.Type) bool {
+	if array, ok := expr.(*ast.ArrayInitializer); ok {
+		c.convertArrayInitializer(array, type_)
+		return true
+	}
+
+	if expr == nil || !expr.Result().IsUntyped() {
+		return true
+	}
+
+	primitive, ok := type_.(*types.PrimitiveType)
+
+	if !ok || !types.IsNumber(primitive.Kind) {
+		// The default type is used and the caller reports the mismatch
+		primitive = expr.Result().Type.(*types.PrimitiveType)
+	}
+
+	value := c.evaluate(expr)
+
+	// Branches of conditions which are not constant are converted separately
+	if value.Kind() == constant.Unknown {
+		switch expr := expr.(type) {
+		case *ast.IfExpr:
+			ok := c.convertUntyped(expr.Then, primitive)
+			ok = c.convertUntyped(expr.Else, primitive) && ok
+
+			expr.Result().SetValue(primitive, 0)
+			return ok
+
+		case *ast.BlockExpr:
+			ok := c.convertUntyped(expr.Value, primitive)
+
+			expr.Result().SetValue(primitive, 0)
+			return ok
+		}
+	}
+
+	converted, ok := c.representable(expr.Range(), value, primitive)
+
+	setUntypedType(expr, primitive)
+	expr.Result().Constant = converted
+
+	return ok
+}
+
+// defaultUntyped converts an untyped constant expression to its default type.
+func (c *checker) defaultUntyped(expr ast.Expr) {
+	if expr.Result().IsUntyped() {
+		c.convertUntyped(expr, expr.Result().Type)
+	}
+}
+
+// representable converts the value to the kind of the type, reporting an error if it does not fit.
+func (c *checker) representable(range_ core.Range, value constant.Value, type_ *types.PrimitiveType) (constant.Value, bool) {
+	converted, problem := fit(value, type_)
+
+	if problem != "" {
+		c.errorRange(range_, "Constant '%s' %s '%s'.", value, problem, type_)
+		return nil, false
+	}
+
+	return converted, true
+}
+
+// fit converts the value to the kind of the type, the problem describes why it does not fit if it is not empty.
+func fit(value constant.Value, type_ *types.PrimitiveType) (constant.Value, string) {
+	if value.Kind() != constant.Int && value.Kind() != constant.Float {
+		return nil, ""
+	}
+
+	if types.IsInteger(type_.Kind) {
+		integer := constant.ToInt(value)
+
+		if integer.Kind() != constant.Int {
+			return nil, "is truncated when converted to"
+		}
+
+		bits := uint(types.GetBitSize(type_.Kind))
+		min, max := constant.MakeInt64(0), constant.Shift(constant.MakeInt64(1), token.SHL, bits)
+
+		if types.IsSigned(type_.Kind) {
+			min = constant.Shift(constant.MakeInt64(-1), token.SHL, bits-1)
+			max = constant.Shift(constant.MakeInt64(1), token.SHL, bits-1)
+		}
+
+		if constant.Compare(integer, token.LSS, min) || constant.Compare(integer, token.GEQ, max) {
+			return nil, "overflows"
+		}
+
+		return integer, ""
+	}
+
+	float := constant.ToFloat(value)
+	limit := constant.MakeFloat64(math.MaxFloat64)
+
+	if type_.Kind == types.F32 {
+		limit = constant.MakeFloat64(math.MaxFloat32)
+	}
+
+	abs := float
+	if constant.Sign(abs) < 0 {
+		abs = constant.UnaryOp(token.SUB, abs, 0)
+	}
+
+	if constant.Compare(abs, token.GTR, limit) {
+		return nil, "overflows"
+	}
+
+	return float, ""
+}
+
+// checkUntypedBinary checks a binary expression with two untyped operands, returns false if the regular checks
+// should be used instead.
+func (c *checker) checkUntypedBinary(expr *ast.Binary) bool {
+	common := commonUntyped(expr.Left.Result().Type, expr.Right.Result().Type)
+
+	switch {
+	case scanner.IsEquality(expr.Op.Kind), scanner.IsComparison(expr.Op.Kind):
+		expr.Result().SetValue(types.Primitive(types.Bool, core.Range{}), 0)
+
+		if value := c.evaluate(expr); value.Kind() == constant.Bool {
+			expr.Result().Constant = value
+		} else {
+			c.convertUntyped(expr.Left, common)
+			c.convertUntyped(expr.Right, common)
+		}
+
+		return true
+
+	case scanner.IsArithmetic(expr.Op.Kind), scanner.IsWrapping(expr.Op.Kind), scanner.IsBitwise(expr.Op.Kind):
+		if !scanner.IsArithmetic(expr.Op.Kind) && !types.IsInteger(common.Kind) {
+			return false
+		}
+
+		expr.Result().SetValue(common, ast.UntypedFlag)
+
+		if value := c.evaluate(expr); value.Kind() == constant.Unknown {
+			if (expr.Op.Kind == scanner.Slash || expr.Op.Kind == scanner.Percentage) && constant.Sign(c.evaluate(expr.Right)) == 0 {
+				c.errorRange(expr.Right.Range(), "Division by zero.")
+				expr.Result().SetInvalid()
+
+				return true
+			}
+
+			// Not a constant, computed at runtime with the common type
+			c.convertUntyped(expr.Left, common)
+			c.convertUntyped(expr.Right, common)
+
+			expr.Result().SetValue(common, 0)
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// commonUntyped returns the type of an operation on two untyped constants, floats win over unsigned integers which
+// win over signed ones.
+func commonUntyped(left, right types.Type) *types.PrimitiveType {
+	rank := func(type_ *types.PrimitiveType) int {
+		switch {
+		case types.IsFloating(type_.Kind):
+			return 2
+		case types.IsUnsigned(type_.Kind):
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	l := left.(*types.PrimitiveType)
+	r := right.(*types.PrimitiveType)
+
+	if rank(r) > rank(l) {
+		return r
+	}
+
+	return l
+}
+
+// setUntypedType sets the type of an untyped constant expression and all of its untyped operands.
+func setUntypedType(expr ast.Expr, type_ types.Type) {
+	if !expr.Result().IsUntyped() {
+		return
+	}
+
+	expr.Result().SetValue(type_, 0)
+
+	switch expr := expr.(type) {
+	case *ast.Group:
+		setUntypedType(expr.Expr, type_)
+
+	case *ast.Unary:
+		setUntypedType(expr.Value, type_)
+
+	case *ast.Binary:
+		setUntypedType(expr.Left, type_)
+		setUntypedType(expr.Right, type_)
+
+	case *ast.IfExpr:
+		setUntypedType(expr.Then, type_)
+		setUntypedType(expr.Else, type_)
+
+	case *ast.BlockExpr:
+		setUntypedType(expr.Value, type_)
+	}
+}
+
+// getSuffixType returns the type of a number literal suffix.
+func getSuffixType(suffix string) (types.PrimitiveKind, bool) {
+	switch suffix {
+	case "u8":
+		return types.U8, true
+	case "u16":
+		return types.U16, true
+	case "u32":
+		return types.U32, true
+	case "u64":
+		return types.U64, true
+
+	case "i8":
+		return types.I8, true
+	case "i16":
+		return types.I16, true
+	case "i32":
+		return types.I32, true
+	case "i64":
+		return types.I64, true
+
+	case "f32":
+		return types.F32, true
+	case "f64":
+		return types.F64, true
+	}
+
+	return 0, false
+}
+
+// untypedDefaulter converts the untyped constants in the direct children of a node, which were not converted by the
+// node itself, to their default type.
+type untypedDefaulter struct {
+	c *checker
+}
+
+func (u untypedDefaulter) AcceptDecl(_ ast.Decl) {}
+
+func (u untypedDefaulter) AcceptStmt(_ ast.Stmt) {}
+
+func (u untypedDefaulter) AcceptExpr(expr ast.Expr) {
+	u.c.defaultUntyped(expr)
+}
@@ -0,0 +1,254 @@
+package checker
+
+import (
+	"fireball/core/ast"
+	"fireball/core/scanner"
+	"fireball/core/types"
+	"fireball/core/utils"
+)
+
+// Declarations, fields and methods are private to the file they are declared in unless they are marked with 'pub'.
+// Private items can only be used in their own file so the ones which are never used there are reported.
+
+type usages struct {
+	local utils.Set[ast.Decl]
+
+	// Private functions, methods and fields
+	items utils.Set[any]
+
+	// Names of private structs and enums, types are copied when they are resolved
+	types utils.Set[string]
+}
+
+func newUsages(decls []ast.Decl) usages {
+	u := usages{
+		local: utils.NewSet[ast.Decl](),
+		items: utils.NewSet[any](),
+		types: utils.NewSet[string](),
+	}
+
+	for _, decl := range decls {
+		u.local.Add(decl)
+	}
+
+	return u
+}
+
+// isLocal returns true if the node is declared in the file being checked.
+func (c *checker) isLocal(node ast.Node) bool {
+	for node.Parent() != nil {
+		node = node.Parent()
+	}
+
+	decl, ok := node.(ast.Decl)
+	return ok && c.usages.local.Contains(decl)
+}
+
+// checkFunctionVisible reports an error if a private function or method declared in a different file is used.
+func (c *checker) checkFunctionVisible(function *ast.Func, name scanner.Token, path string) {
+	if c.isLocal(function) {
+		c.usages.items.Add(function)
+	} else if !function.Pub {
+		if function.Method() != nil || function.IsStatic() {
+			c.errorToken(name, "Method '%s' is private to '%s'.", name, path)
+		} else {
+			c.errorToken(name, "Function '%s' is private to '%s'.", name, path)
+		}
+	}
+}
+
+// checkTypeVisible reports an error if a private struct or enum declared in a different file is used.
+func (c *checker) checkTypeVisible(type_ types.Type, name scanner.Token, path string) {
+	var decl ast.Decl
+	pub := false
+
+	switch type_ := type_.(type) {
+	case *ast.Struct:
+		decl, pub = type_, type_.Pub
+	case *ast.Enum:
+		decl, pub = type_, type_.Pub
+	default:
+		return
+	}
+
+	if c.isLocal(decl) {
+		// Derived methods referencing their own struct do not count as a usage
+		if !c.inDerivedImpl() {
+			c.usages.types.Add(name.Lexeme)
+		}
+	} else if !pub {
+		c.errorToken(name, "Type '%s' is private to '%s'.", name, path)
+	}
+}
+
+// inDerivedImpl returns true while checking a method generated by a 'Derive' attribute.
+func (c *checker) inDerivedImpl() bool {
+	if c.function == nil {
+		return false
+	}
+
+	impl, ok := c.function.Parent().(*ast.Impl)
+	return ok && impl.Derived
+}
+
+// checkFieldVisible reports an error if a private field of a struct declared in a different file is used.
+func (c *checker) checkFieldVisible(field *ast.Field, name scanner.Token) {
+	if path, private := c.fieldPrivate(field); private {
+		c.errorToken(name, "Field '%s' of struct '%s' is private to '%s'.", name, field.Parent.Name, path)
+	}
+}
+
+// checkEmbeddedVisible reports a member promoted through a private embedded field, the error names the embedded field
+// instead of the member.
+func (c *checker) checkEmbeddedVisible(field *ast.Field, name scanner.Token) {
+	if path, private := c.fieldPrivate(field); private {
+		c.errorToken(name, "Embedded field '%s' of struct '%s' is private to '%s'.", field.Name, field.Parent.Name, path)
+	}
+}
+
+// fieldPrivate returns true and the path of the file declaring the struct if the field can't be accessed from this
+// file, fields of local structs are marked as used.
+func (c *checker) fieldPrivate(field *ast.Field) (string, bool) {
+	if c.isLocal(field.Parent) {
+		c.usages.items.Add(field)
+		return "", false
+	}
+
+	if field.Pub {
+		return "", false
+	}
+
+	_, path := c.resolver.GetType(field.Parent.QualifiedName())
+	if path == "" {
+		_, path = c.resolver.GetType(field.Parent.Name.Lexeme)
+	}
+
+	return path, true
+}
+
+// reportUnused reports private items of the file that were never used.
+func (c *checker) reportUnused() {
+	collector := &typeUsages{c: c}
+
+	for _, decl := range c.decls {
+		collector.AcceptDecl(decl)
+	}
+
+	for _, decl := range c.decls {
+		switch decl := decl.(type) {
+		case *ast.Struct:
+			if !decl.Pub && !c.usages.types.Contains(decl.Name.Lexeme) && isReported(decl.Name) {
+				c.warningToken(decl.Name, "Unused private struct '%s'.", decl.Name)
+			}
+
+			for _, fields := range [][]ast.Field{decl.StaticFields, decl.Fields} {
+				for i := range fields {
+					field := &fields[i]
+
+					if !field.Pub && !c.usages.items.Contains(field) && isReported(field.Name) {
+						c.warningToken(field.Name, "Unused private field '%s'.", field.Name)
+					}
+				}
+			}
+
+		case *ast.Enum:
+			if !decl.Pub && !c.usages.types.Contains(decl.Name.Lexeme) && isReported(decl.Name) {
+				c.warningToken(decl.Name, "Unused private enum '%s'.", decl.Name)
+			}
+
+		case *ast.Impl:
+			for _, function := range decl.Functions {
+				if function, ok := function.(*ast.Func); ok && !function.Pub && !c.usages.items.Contains(function) && !isExported(function) && isReported(function.Name) {
+					c.warningToken(function.Name, "Unused private method '%s'.", function.Name)
+				}
+			}
+
+		case *ast.Func:
+			if !decl.Pub && !c.usages.items.Contains(decl) && decl.Name.Lexeme != "main" && !isExported(decl) && !isTest(decl) && isReported(decl.Name) {
+				c.warningToken(decl.Name, "Unused private function '%s'.", decl.Name)
+			}
+		}
+	}
+}
+
+// isReported returns false for names prefixed with '_', like for variables they are not reported when unused.
+func isReported(name scanner.Token) bool {
+	return name.Lexeme != "" && name.Lexeme[0] != '_'
+}
+
+// isExported returns true for functions called from outside of Fireball.
+func isExported(function *ast.Func) bool {
+	var export types.ExportAttribute
+	return function.GetAttribute(&export)
+}
+
+// isTest returns true for functions called by the test or benchmark runner.
+func isTest(function *ast.Func) bool {
+	var test types.TestAttribute
+	var bench types.BenchAttribute
+
+	return function.GetAttribute(&test) || function.GetAttribute(&bench)
+}
+
+// typeUsages marks private structs and enums used in type positions of a file.
+type typeUsages struct {
+	c *checker
+}
+
+func (t *typeUsages) VisitType(type_ types.Type) {
+	switch type_ := type_.(type) {
+	case *ast.Struct:
+		t.c.usages.types.Add(type_.Name.Lexeme)
+
+	case *ast.Enum:
+		t.c.usages.types.Add(type_.Name.Lexeme)
+
+	default:
+		type_.AcceptTypes(t)
+	}
+}
+
+func (t *typeUsages) AcceptDecl(decl ast.Decl) {
+	if impl, ok := decl.(*ast.Impl); ok && impl.Derived {
+		return
+	}
+
+	decl.AcceptChildren(t)
+
+	// The field types of a struct referencing the struct itself do not count
+	if struct_, ok := decl.(*ast.Struct); ok {
+		for _, fields := range [][]ast.Field{struct_.StaticFields, struct_.Fields} {
+			for _, field := range fields {
+				if field.Type != nil && !isStruct(field.Type, struct_) {
+					t.VisitType(field.Type)
+				}
+			}
+		}
+	} else {
+		decl.AcceptTypes(t)
+	}
+}
+
+func (t *typeUsages) AcceptStmt(stmt ast.Stmt) {
+	stmt.AcceptChildren(t)
+	stmt.AcceptTypes(t)
+}
+
+func (t *typeUsages) AcceptExpr(expr ast.Expr) {
+	expr.AcceptChildren(t)
+	expr.AcceptTypes(t)
+}
+
+// isStruct returns true if the type is the struct or a pointer or array of it.
+func isStruct(type_ types.Type, struct_ *ast.Struct) bool {
+	switch type_ := type_.(type) {
+	case *ast.Struct:
+		return type_.Name.Lexeme == struct_.Name.Lexeme
+	case *types.PointerType:
+		return isStruct(type_.Pointee, struct_)
+	case *types.ArrayType:
+		return isStruct(type_.Base, struct_)
+	}
+
+	return false
+}
@@ -0,0 +1,173 @@
+package codegen
+
+import (
+	"fireball/core/llvm"
+	"fireball/core/scanner"
+	"fireball/core/types"
+	"fmt"
+)
+
+// Name of the runtime function called when a check fails, defined by the runtime module generated by the build command.
+const PanicFunction = "__fireball_panic"
+
+func (c *codegen) overflowChecks() bool {
+	return c.options.OverflowChecks && !c.unchecked
+}
+
+func (c *codegen) safetyChecks() bool {
+	return c.options.SafetyChecks && !c.unchecked
+}
+
+// Overflow
+
+func (c *codegen) checkedBinary(kind llvm.BinaryKind, left, right llvm.Value, type_ *types.PrimitiveType, op scanner.Token) llvm.Value {
+	// Get intrinsic
+	name := ""
+
+	switch kind {
+	case llvm.Add:
+		name = "add"
+	case llvm.Sub:
+		name = "sub"
+	case llvm.Mul:
+		name = "mul"
+
+	default:
+		panic("codegen.checkedBinary() - Invalid operator kind")
+	}
+
+	name = fmt.Sprintf("llvm.%s%s.with.overflow.i%d", ternary(types.IsSigned(type_.Kind), "s", "u"), name, types.GetBitSize(type_.Kind))
+
+	valueType := c.getType(type_)
+	resultType := c.module.LiteralStruct([]llvm.Type{valueType, c.getPrimitiveType(types.Bool)})
+
+	intrinsic := c.getRuntimeFunction(name, []llvm.Type{valueType, valueType}, resultType)
+
+	// Call
+	result := c.block.Call(intrinsic, []llvm.Value{left, right}, resultType)
+	result.SetLocation(op)
+
+	value := c.block.ExtractValue(result, 0)
+	value.SetLocation(op)
+
+	overflow := c.block.ExtractValue(result, 1)
+	overflow.SetLocation(op)
+
+	c.panicIf(overflow, "Integer overflow.", op)
+
+	return value
+}
+
+// Safety
+
+func (c *codegen) checkNull(pointer llvm.Value, location scanner.Token) {
+	if !c.safetyChecks() {
+		return
+	}
+
+	null := c.block.Binary(llvm.Eq, pointer, c.function.LiteralRaw(pointer.Type(), "null"))
+	null.SetLocation(location)
+
+	c.panicIf(null, "Null pointer dereference.", location)
+}
+
+func (c *codegen) checkDivisor(divisor llvm.Value, location scanner.Token) {
+	if !c.safetyChecks() {
+		return
+	}
+
+	zero := c.block.Binary(llvm.Eq, divisor, c.function.Literal(divisor.Type(), llvm.Literal{}))
+	zero.SetLocation(location)
+
+	c.panicIf(zero, "Division by zero.", location)
+}
+
+func (c *codegen) checkBounds(index llvm.Value, indexType types.Type, count uint32, location scanner.Token) {
+	if !c.safetyChecks() {
+		return
+	}
+
+	v, ok := indexType.(*types.PrimitiveType)
+	if !ok {
+		return
+	}
+
+	bits := types.GetBitSize(v.Kind)
+	var outOfBounds llvm.Value
+
+	// index < 0
+	if types.IsSigned(v.Kind) {
+		negative := c.block.Binary(llvm.Lt, index, c.function.Literal(index.Type(), llvm.Literal{}))
+		negative.SetLocation(location)
+
+		outOfBounds = negative
+		bits--
+	}
+
+	// index >= count, only needed if the count fits inside the index type
+	if bits >= 64 || uint64(count) <= (uint64(1)<<bits)-1 {
+		tooBig := c.block.Binary(llvm.Ge, index, c.function.Literal(index.Type(), llvm.Literal{Signed: int64(count), Unsigned: uint64(count)}))
+		tooBig.SetLocation(location)
+
+		if outOfBounds == nil {
+			outOfBounds = tooBig
+		} else {
+			or := c.block.Binary(llvm.Or, outOfBounds, tooBig)
+			or.SetLocation(location)
+
+			outOfBounds = or
+		}
+	}
+
+	if outOfBounds != nil {
+		c.panicIf(outOfBounds, "Index out of bounds.", location)
+	}
+}
+
+// Panic
+
+func (c *codegen) panicIf(condition llvm.Value, message string, location scanner.Token) {
+	// Get blocks
+	fail := c.function.Block("check.fail")
+	ok := c.function.Block("check.ok")
+
+	c.block.Br(condition, fail, ok).SetLocation(location)
+
+	// Fail
+	c.beginBlock(fail)
+
+	u8 := types.PrimitiveType{Kind: types.U8}
+	ptr := types.PointerType{Pointee: &u8}
+
+	ptrType := c.getType(&ptr)
+	i32Type := c.getPrimitiveType(types.I32)
+	voidType := c.getPrimitiveType(types.Void)
+
+	function := c.getRuntimeFunction(PanicFunction, []llvm.Type{ptrType, ptrType, i32Type, i32Type}, voidType)
+
+	call := c.block.Call(function, []llvm.Value{
+		c.module.Constant(message),
+		c.module.Constant(c.path),
+		c.function.Literal(i32Type, llvm.Literal{Signed: int64(location.Line())}),
+		c.function.Literal(i32Type, llvm.Literal{Signed: int64(location.Column() + 1)}),
+	}, voidType)
+
+	call.SetLocation(location)
+	c.block.Unreachable()
+
+	// Ok
+	c.beginBlock(ok)
+}
+
+// Runtime functions
+
+func (c *codegen) getRuntimeFunction(name string, parameters []llvm.Type, returns llvm.Type) llvm.Value {
+	if value, ok := c.runtimeFunctions[name]; ok {
+		return value
+	}
+
+	value := c.module.Declare(c.module.Function(name, parameters, false, returns))
+	c.runtimeFunctions[name] = value
+
+	return value
+}
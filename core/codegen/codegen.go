@@ -5,27 +5,34 @@ import (
 	"fireball/core/ast"
 	"fireball/core/llvm"
 	"fireball/core/scanner"
+	"fireball/core/target"
 	"fireball/core/types"
 	"fireball/core/utils"
 	"io"
+	"strings"
 )
 
 type codegen struct {
 	path     string
 	resolver utils.Resolver
+	target   target.Target
+	options  Options
 
-	types []typePair
+	types       []typePair
+	boundMethod llvm.Type
 
-	staticVariables map[*ast.Field]exprValue
-	functions       map[*ast.Func]llvm.Value
+	staticVariables  map[*ast.Field]exprValue
+	functions        map[*ast.Func]llvm.Value
+	runtimeFunctions map[string]llvm.Value
 
 	scopes    []scope
 	variables []variable
 
 	allocas map[ast.Node]exprValue
 
-	function *llvm.Function
-	block    *llvm.Block
+	function  *llvm.Function
+	block     *llvm.Block
+	unchecked bool
 
 	loopStart *llvm.Block
 	loopEnd   *llvm.Block
@@ -56,21 +63,39 @@ type variable struct {
 	value exprValue
 }
 
-func Emit(path string, resolver utils.Resolver, decls []ast.Decl, writer io.Writer) {
+type Options struct {
+	// Emit overflow checked integer arithmetic for +, - and * (wrapping operators are never checked)
+	OverflowChecks bool
+
+	// Emit array bounds, null pointer and division by zero checks
+	SafetyChecks bool
+
+	// Emit debug info for functions, variables and instructions
+	DebugInfo bool
+}
+
+func Emit(path string, resolver utils.Resolver, decls []ast.Decl, options Options, writer io.Writer) {
 	// Init codegen
 	c := &codegen{
 		path:     path,
 		resolver: resolver,
+		target:   resolver.GetTarget(),
+		options:  options,
 
-		staticVariables: make(map[*ast.Field]exprValue),
-		functions:       make(map[*ast.Func]llvm.Value),
+		staticVariables:  make(map[*ast.Field]exprValue),
+		functions:        make(map[*ast.Func]llvm.Value),
+		runtimeFunctions: make(map[string]llvm.Value),
 
-		module: llvm.NewModule(),
+		module: llvm.NewModule(resolver.GetTarget()),
 	}
 
 	// File metadata
 	c.module.Source(path)
 
+	if !options.DebugInfo {
+		c.module.StripDebugInfo()
+	}
+
 	// Find some declarations
 	for _, decl := range decls {
 		switch decl := decl.(type) {
@@ -107,7 +132,7 @@ func (c *codegen) defineOrDeclare(function *ast.Func) {
 		// Define
 		this := function.Method()
 
-		f := c.module.Define(t, function.MangledName()[3:])
+		f := c.module.Define(t, strings.TrimPrefix(function.MangledName(), "fb$"))
 		c.functions[function] = f
 
 		// Inline
@@ -141,7 +166,7 @@ func (c *codegen) defineOrDeclare(function *ast.Func) {
 func (c *codegen) load(value exprValue, type_ types.Type) exprValue {
 	if value.addressable {
 		load := c.block.Load(value.v)
-		load.SetAlign(type_.Align())
+		load.SetAlign(type_.Align(c.target))
 
 		return exprValue{
 			v:           load,
@@ -194,9 +219,9 @@ func (c *codegen) getFunction(function *ast.Func) exprValue {
 	}
 
 	// Resolve function from project
-	_, filePath := c.resolver.GetFunction(function.Name.Lexeme)
+	local, filePath := c.resolver.GetFunction(function.Name.Lexeme)
 
-	if filePath == c.path {
+	if local == function && filePath == c.path {
 		panic("codegen.getFunction() - Local function not found in functions map")
 	}
 
@@ -229,7 +254,7 @@ func (a *allocaFinder) AcceptStmt(stmt ast.Stmt) {
 	if variable, ok := stmt.(*ast.Variable); ok {
 		pointer := a.c.block.Alloca(a.c.getType(variable.Type))
 		pointer.SetName(variable.Name.Lexeme + ".var")
-		pointer.SetAlign(variable.Type.Align())
+		pointer.SetAlign(variable.Type.Align(a.c.target))
 
 		a.c.allocas[variable] = exprValue{
 			v:           pointer,
@@ -247,7 +272,7 @@ func (a *allocaFinder) AcceptExpr(expr ast.Expr) {
 			returns := expr.Callee.Result().Function.Returns
 
 			pointer := a.c.block.Alloca(a.c.getType(returns))
-			pointer.SetAlign(returns.Align())
+			pointer.SetAlign(returns.Align(a.c.target))
 
 			a.c.allocas[expr] = exprValue{
 				v:           pointer,
@@ -260,7 +285,7 @@ func (a *allocaFinder) AcceptExpr(expr ast.Expr) {
 			type_ := expr.Value.Result().Type
 
 			pointer := a.c.block.Alloca(a.c.getType(type_))
-			pointer.SetAlign(type_.Align())
+			pointer.SetAlign(type_.Align(a.c.target))
 
 			a.c.allocas[expr] = exprValue{
 				v:           pointer,
@@ -340,6 +365,18 @@ func (c *codegen) getType(type_ types.Type) llvm.Type {
 	} else if v, ok := type_.(*types.PointerType); ok {
 		// Pointer
 		llvmType = c.module.Pointer(v.String(), c.getType(v.Pointee))
+	} else if v, ok := type_.(*ast.Func); ok && v.IsBound() {
+		// Bound method, all of them share the same layout
+		if c.boundMethod == nil {
+			pointer := types.PointerType{Pointee: &types.PrimitiveType{Kind: types.U8}}
+
+			c.boundMethod = c.module.Struct("BoundMethod", v.Size(c.target)*8, []llvm.Field{
+				{Name: "function", Type: c.getType(&pointer), Offset: 0},
+				{Name: "this", Type: c.getType(&pointer), Offset: pointer.Size(c.target) * 8},
+			})
+		}
+
+		llvmType = c.boundMethod
 	} else if v, ok := type_.(*ast.Func); ok {
 		// Function
 		var parameters []llvm.Type
@@ -382,7 +419,7 @@ func (c *codegen) getType(type_ types.Type) llvm.Type {
 		llvmType = c.module.Function(getMangledName(v), parameters, v.IsVariadic(), returns)
 	} else if v, ok := type_.(*ast.Struct); ok {
 		// Struct
-		layout := architecture.CLayout{}
+		layout := architecture.CLayout{Target: c.target}
 		fields := make([]llvm.Field, len(v.Fields))
 
 		for i, field := range v.Fields {
@@ -395,10 +432,10 @@ func (c *codegen) getType(type_ types.Type) llvm.Type {
 			}
 		}
 
-		llvmType = c.module.Struct(v.Name.Lexeme, layout.Size()*8, fields)
+		llvmType = c.module.Struct(v.QualifiedName(), layout.Size()*8, fields)
 	} else if v, ok := type_.(*ast.Enum); ok {
 		// Enum
-		llvmType = c.module.Alias(v.Name.Lexeme, c.getType(v.Type))
+		llvmType = c.module.Alias(v.QualifiedName(), c.getType(v.Type))
 	}
 
 	if llvmType != nil {
@@ -613,6 +650,11 @@ func (c *codegen) acceptStmt(stmt ast.Stmt) {
 
 func (c *codegen) acceptExpr(expr ast.Expr) exprValue {
 	if expr != nil {
+		// Folded untyped constant
+		if value := expr.Result().Constant; value != nil {
+			return exprValue{v: c.constant(value, expr.Result().Type)}
+		}
+
 		expr.Accept(c)
 		return c.exprResult
 	}
@@ -19,6 +19,9 @@ func (c *codegen) VisitImpl(impl *ast.Impl) {
 func (c *codegen) VisitEnum(_ *ast.Enum) {
 }
 
+func (c *codegen) VisitStaticAssert(_ *ast.StaticAssert) {
+}
+
 func (c *codegen) VisitFunc(decl *ast.Func) {
 	// Get function
 	var function *llvm.Function
@@ -34,7 +37,10 @@ func (c *codegen) VisitFunc(decl *ast.Func) {
 	}
 
 	// Setup state
+	var unchecked types.UncheckedAttribute
+
 	c.function = function
+	c.unchecked = decl.GetAttribute(&unchecked)
 	c.beginBlock(function.Block("entry"))
 
 	c.pushScope()
@@ -55,10 +61,10 @@ func (c *codegen) VisitFunc(decl *ast.Func) {
 
 		pointer := c.block.Alloca(c.getType(param.Type))
 		pointer.SetName(param.Name.Lexeme + ".var")
-		pointer.SetAlign(param.Type.Align())
+		pointer.SetAlign(param.Type.Align(c.target))
 
 		store := c.block.Store(pointer, function.GetParameter(index))
-		store.SetAlign(param.Type.Align())
+		store.SetAlign(param.Type.Align(c.target))
 
 		c.addVariable(param.Name, exprValue{v: pointer})
 	}
@@ -5,9 +5,9 @@ import (
 	"fireball/core/llvm"
 	"fireball/core/scanner"
 	"fireball/core/types"
+	"go/constant"
 	"log"
 	"strconv"
-	"strings"
 )
 
 func (c *codegen) VisitGroup(expr *ast.Group) {
@@ -26,61 +26,17 @@ func (c *codegen) VisitLiteral(expr *ast.Literal) {
 	case scanner.True, scanner.False:
 		value = c.function.LiteralRaw(type_, expr.Value.Lexeme)
 
-	case scanner.Number:
-		raw := expr.Value.Lexeme
-		last := raw[len(raw)-1]
-
-		if last == 'f' || last == 'F' {
-			v, _ := strconv.ParseFloat(raw[:len(raw)-1], 32)
-			value = c.function.Literal(type_, llvm.Literal{Floating: v})
-		} else if strings.ContainsRune(raw, '.') {
-			v, _ := strconv.ParseFloat(raw, 64)
-			value = c.function.Literal(type_, llvm.Literal{Floating: v})
-		} else {
-			t := expr.Result().Type.(*types.PrimitiveType)
-
-			if types.IsSigned(t.Kind) {
-				v, _ := strconv.ParseInt(raw, 10, 64)
-				value = c.function.Literal(type_, llvm.Literal{Signed: v})
-			} else {
-				v, _ := strconv.ParseUint(raw, 10, 64)
-				value = c.function.Literal(type_, llvm.Literal{Unsigned: v})
-			}
-		}
-
-	case scanner.Hex:
-		v, _ := strconv.ParseUint(expr.Value.Lexeme[2:], 16, 64)
-		value = c.function.Literal(type_, llvm.Literal{Unsigned: v})
-
-	case scanner.Binary:
-		v, _ := strconv.ParseUint(expr.Value.Lexeme[2:], 2, 64)
-		value = c.function.Literal(type_, llvm.Literal{Unsigned: v})
+	case scanner.Number, scanner.Hex, scanner.Binary, scanner.Octal:
+		v, _ := scanner.ParseNumber(expr.Value)
+		value = c.constant(v, expr.Result().Type)
 
 	case scanner.Character:
-		char := expr.Value.Lexeme[1 : len(expr.Value.Lexeme)-1]
-		var number uint8
-
-		switch char {
-		case "'":
-			number = '\''
-		case "\\0":
-			number = '\000'
-
-		case "\\n":
-			number = '\n'
-		case "\\r":
-			number = '\r'
-		case "\\t":
-			number = '\t'
-
-		default:
-			number = char[0]
-		}
-
-		value = c.function.Literal(type_, llvm.Literal{Unsigned: uint64(number)})
+		char, _ := scanner.ParseCharacter(expr.Value)
+		value = c.function.Literal(type_, llvm.Literal{Unsigned: uint64(char)})
 
 	case scanner.String:
-		value = c.module.Constant(expr.Value.Lexeme[1 : len(expr.Value.Lexeme)-1])
+		str, _ := scanner.ParseString(expr.Value)
+		value = c.module.Constant(str)
 
 	default:
 		panic("codegen.VisitLiteral() - Invalid literal kind")
@@ -92,6 +48,36 @@ func (c *codegen) VisitLiteral(expr *ast.Literal) {
 	}
 }
 
+// constant converts an integer, float or bool constant into a LLVM IR constant of the specified type.
+func (c *codegen) constant(value constant.Value, type_ types.Type) llvm.Value {
+	t := c.getType(type_)
+
+	switch value.Kind() {
+	case constant.Bool:
+		return c.function.LiteralRaw(t, strconv.FormatBool(constant.BoolVal(value)))
+
+	case constant.Float:
+		if types.IsPrimitive(type_, types.F32) {
+			v, _ := constant.Float32Val(value)
+			return c.function.Literal(t, llvm.Literal{Floating: float64(v)})
+		}
+
+		v, _ := constant.Float64Val(value)
+		return c.function.Literal(t, llvm.Literal{Floating: v})
+
+	default:
+		if isFloating(type_) {
+			v, _ := constant.Float64Val(constant.ToFloat(value))
+			return c.constant(constant.MakeFloat64(v), type_)
+		}
+
+		signed, _ := constant.Int64Val(value)
+		unsigned, _ := constant.Uint64Val(value)
+
+		return c.function.Literal(t, llvm.Literal{Signed: signed, Unsigned: unsigned})
+	}
+}
+
 func (c *codegen) VisitStructInitializer(expr *ast.StructInitializer) {
 	// Value
 	struct_ := expr.Target.(*ast.Struct)
@@ -120,13 +106,13 @@ func (c *codegen) VisitStructInitializer(expr *ast.StructInitializer) {
 			malloc.v,
 			[]llvm.Value{c.function.Literal(
 				c.getType(mallocFunc.Params[0].Type),
-				llvm.Literal{Unsigned: uint64(struct_.Size())},
+				llvm.Literal{Unsigned: uint64(struct_.Size(c.target))},
 			)},
 			c.getType(mallocFunc.Returns),
 		)
 
 		store := c.block.Store(pointer, result)
-		store.SetAlign(struct_.Align())
+		store.SetAlign(struct_.Align(c.target))
 
 		c.exprResult = exprValue{v: pointer}
 	}
@@ -171,7 +157,7 @@ func (c *codegen) VisitNewArray(expr *ast.NewArray) {
 			llvm.Mul,
 			c.function.Literal(
 				c.getType(mallocFunc.Params[0].Type),
-				llvm.Literal{Unsigned: uint64(expr.Type_.Size())},
+				llvm.Literal{Unsigned: uint64(expr.Type_.Size(c.target))},
 			),
 			count.v,
 		)},
@@ -227,6 +213,16 @@ func (c *codegen) VisitUnary(expr *ast.Unary) {
 			}
 
 		case scanner.Ampersand, scanner.FuncPtr:
+			// Bound method
+			if function, ok := expr.Result().Type.(*ast.Func); ok && function.IsBound() {
+				bound := c.function.LiteralRaw(c.getType(function), "zeroinitializer")
+				bound = c.block.InsertValue(bound, value.v, 0)
+				bound = c.block.InsertValue(bound, c.this.v, 1)
+
+				c.exprResult = exprValue{v: bound}
+				return
+			}
+
 			c.exprResult = exprValue{
 				v:           value.v,
 				addressable: false,
@@ -236,10 +232,11 @@ func (c *codegen) VisitUnary(expr *ast.Unary) {
 
 		case scanner.Star:
 			result = c.load(value, expr.Value.Result().Type).v
+			c.checkNull(result, expr.Token())
 
 			if _, ok := expr.Parent().(*ast.Assignment); !ok {
 				load := c.block.Load(result)
-				load.SetAlign(expr.Result().Type.Align())
+				load.SetAlign(expr.Result().Type.Align(c.target))
 
 				result = load
 			}
@@ -260,7 +257,7 @@ func (c *codegen) VisitUnary(expr *ast.Unary) {
 			)
 
 			store := c.block.Store(value.v, newValue.v)
-			store.SetAlign(expr.Value.Result().Type.Align())
+			store.SetAlign(expr.Value.Result().Type.Align(c.target))
 			store.SetLocation(expr.Token())
 
 			result = newValue.v
@@ -289,7 +286,7 @@ func (c *codegen) VisitUnary(expr *ast.Unary) {
 			)
 
 			store := c.block.Store(value.v, newValue.v)
-			store.SetAlign(expr.Value.Result().Type.Align())
+			store.SetAlign(expr.Value.Result().Type.Align(c.target))
 			store.SetLocation(expr.Token())
 
 			result = prevValue.v
@@ -359,6 +356,78 @@ func (c *codegen) VisitLogical(expr *ast.Logical) {
 	}
 }
 
+func (c *codegen) VisitIfExpr(expr *ast.IfExpr) {
+	// Get blocks
+	then := c.function.Block("if.then")
+	else_ := c.function.Block("if.else")
+	end := c.function.Block("if.end")
+
+	// Condition
+	condition := c.loadExpr(expr.Condition)
+	c.block.Br(condition.v, then, else_).SetLocation(expr.Token())
+
+	// Then, the branches can end in a different block than they started in or leave the function or loop early
+	c.beginBlock(then)
+	thenValue := c.loadExpr(expr.Then)
+	thenBlock := c.block
+	thenFalls := !thenBlock.Terminated()
+	c.block.Br(nil, end, nil).SetLocation(expr.Token())
+
+	// Else
+	c.beginBlock(else_)
+	elseValue := c.loadExpr(expr.Else)
+	elseBlock := c.block
+	elseFalls := !elseBlock.Terminated()
+	c.block.Br(nil, end, nil).SetLocation(expr.Token())
+
+	// End
+	c.beginBlock(end)
+
+	if types.IsPrimitive(expr.Result().Type, types.Void) {
+		c.exprResult = exprValue{}
+		return
+	}
+
+	// Only branches reaching the end are incoming values of the phi
+	switch {
+	case thenFalls && elseFalls:
+		result := c.block.Phi(thenValue.v, thenBlock, elseValue.v, elseBlock)
+		result.SetLocation(expr.Token())
+
+		c.exprResult = exprValue{v: result}
+
+	case thenFalls:
+		c.exprResult = thenValue
+
+	case elseFalls:
+		c.exprResult = elseValue
+
+	default:
+		c.block.Unreachable()
+		c.exprResult = exprValue{v: c.function.LiteralRaw(c.getType(expr.Result().Type), "undef")}
+	}
+}
+
+func (c *codegen) VisitBlockExpr(expr *ast.BlockExpr) {
+	c.pushScope()
+	c.module.PushScope(expr.Token())
+
+	for _, stmt := range expr.Stmts {
+		c.acceptStmt(stmt)
+	}
+
+	value := exprValue{}
+
+	if expr.Value != nil {
+		value = c.loadExpr(expr.Value)
+	}
+
+	c.module.PopScope()
+	c.popScope()
+
+	c.exprResult = value
+}
+
 func (c *codegen) VisitIdentifier(expr *ast.Identifier) {
 	switch expr.Kind {
 	case ast.FunctionKind:
@@ -396,7 +465,7 @@ func (c *codegen) VisitAssignment(expr *ast.Assignment) {
 
 	// Store
 	store := c.block.Store(assignee.v, value.v)
-	store.SetAlign(expr.Result().Type.Align())
+	store.SetAlign(expr.Result().Type.Align(c.target))
 	store.SetLocation(expr.Token())
 
 	c.exprResult = assignee
@@ -429,10 +498,25 @@ func (c *codegen) VisitCast(expr *ast.Cast) {
 		}
 	}
 
-	if _, ok := expr.Expr.Result().Type.(*types.PointerType); ok {
-		if _, ok := expr.Result().Type.(*types.PointerType); ok {
+	if from, ok := expr.Expr.Result().Type.(*types.PointerType); ok {
+		if to, ok := expr.Result().Type.(*types.PointerType); ok {
 			// pointer to pointer
-			c.exprResult = value
+			if from.Pointee.Equals(to.Pointee) {
+				c.exprResult = value
+				return
+			}
+
+			// Embedded struct
+			if path := embeddedPath(from, to); path != nil {
+				c.exprResult = exprValue{v: c.embeddedPointer(c.load(value, from).v, from.Pointee, path, expr.Token())}
+				return
+			}
+
+			// Loads through the pointer need to use the new pointee type
+			result := c.block.Cast(llvm.Bitcast, c.load(value, from).v, c.getType(to))
+			result.SetLocation(expr.Token())
+
+			c.exprResult = exprValue{v: result}
 			return
 		}
 	}
@@ -469,14 +553,16 @@ func (c *codegen) castPrimitiveToPrimitive(value exprValue, from, to types.Type,
 
 		if (types.IsInteger(fromKind) || fromKind == types.Bool) && types.IsInteger(toKind) {
 			// integer / bool to integer
-			if from.Size() > to.Size() {
+			if from.Size(c.target) > to.Size(c.target) {
 				kind = llvm.Trunc
+			} else if types.IsSigned(fromKind) {
+				kind = llvm.SExt
 			} else {
 				kind = llvm.ZExt
 			}
 		} else if types.IsFloating(fromKind) && types.IsFloating(toKind) {
 			// floating to floating
-			if from.Size() > to.Size() {
+			if from.Size(c.target) > to.Size(c.target) {
 				kind = llvm.FpTrunc
 			} else {
 				kind = llvm.FpExt
@@ -511,10 +597,10 @@ func (c *codegen) VisitTypeCall(expr *ast.TypeCall) {
 
 	switch expr.Name.Lexeme {
 	case "sizeof":
-		value = expr.Target.Size()
+		value = expr.Target.Size(c.target)
 
 	case "alignof":
-		value = expr.Target.Align()
+		value = expr.Target.Align(c.target)
 
 	default:
 		panic("codegen.VisitTypeCall() - Invalid name")
@@ -528,32 +614,70 @@ func (c *codegen) VisitTypeCall(expr *ast.TypeCall) {
 	}
 }
 
+func (c *codegen) VisitAssert(expr *ast.Assert) {
+	condition := c.loadExpr(expr.Condition)
+
+	t := types.PrimitiveType{Kind: types.Bool}
+
+	failed := c.block.Binary(
+		llvm.Xor,
+		c.function.Literal(
+			c.getType(&t),
+			llvm.Literal{Signed: 1},
+		),
+		condition.v,
+	)
+
+	failed.SetLocation(expr.Token())
+
+	// Assertions are checked regardless of the safety check options
+	message := "Assertion failed."
+
+	if expr.Message.Kind == scanner.String {
+		str, _ := scanner.ParseString(expr.Message)
+		message = "Assertion failed: " + str
+	}
+
+	c.panicIf(failed, message, expr.Token())
+
+	c.exprResult = exprValue{}
+}
+
 func (c *codegen) VisitCall(expr *ast.Call) {
 	// Get type
 	callee := c.acceptExpr(expr.Callee)
 
 	function := expr.Callee.Result().Function
+	this := c.this
 
 	if f, ok := expr.Callee.Result().Type.(*ast.Func); ok && function == nil {
 		function = f
 		callee = c.load(callee, expr.Callee.Result().Type)
+
+		// Bound method
+		if f.IsBound() {
+			this = exprValue{v: c.block.ExtractValue(callee.v, 1)}
+			callee = exprValue{v: c.block.ExtractValue(callee.v, 0)}
+		}
 	}
 
+	hasThis := function.Method() != nil || function.IsBound()
+
 	// Load arguments
 	argCount := len(expr.Args)
-	if function.Method() != nil {
+	if hasThis {
 		argCount++
 	}
 
 	args := make([]llvm.Value, argCount)
 
-	if function.Method() != nil {
-		args[0] = c.this.v
+	if hasThis {
+		args[0] = this.v
 	}
 
 	for i, arg := range expr.Args {
 		index := i
-		if function.Method() != nil {
+		if hasThis {
 			index++
 		}
 
@@ -580,7 +704,7 @@ func (c *codegen) VisitCall(expr *ast.Call) {
 		pointer := c.allocas[expr]
 
 		store := c.block.Store(pointer.v, c.exprResult.v)
-		store.SetAlign(function.Returns.Align())
+		store.SetAlign(function.Returns.Align(c.target))
 
 		c.exprResult = pointer
 	}
@@ -592,9 +716,12 @@ func (c *codegen) VisitIndex(expr *ast.Index) {
 
 	if pointer, ok := expr.Value.Result().Type.(*types.PointerType); ok {
 		load := c.block.Load(value.v)
-		load.SetAlign(pointer.Pointee.Align())
+		load.SetAlign(pointer.Pointee.Align(c.target))
 
 		value = exprValue{v: load}
+		c.checkNull(value.v, expr.Token())
+	} else if array, ok := expr.Value.Result().Type.(*types.ArrayType); ok {
+		c.checkBounds(index.v, expr.Index.Result().Type, array.Count, expr.Token())
 	}
 
 	t := types.PointerType{Pointee: expr.Result().Type}
@@ -660,12 +787,14 @@ func (c *codegen) VisitMember(expr *ast.Member) {
 				s = v
 
 				load := c.block.Load(value.v)
-				load.SetAlign(v.Align())
+				load.SetAlign(v.Align(c.target))
 
 				value = exprValue{
 					v:           load,
 					addressable: true,
 				}
+
+				c.checkNull(load, expr.Token())
 			}
 		}
 
@@ -679,7 +808,7 @@ func (c *codegen) VisitMember(expr *ast.Member) {
 				pointer := c.allocas[expr]
 
 				store := c.block.Store(pointer.v, value.v)
-				store.SetAlign(expr.Result().Function.Returns.Align())
+				store.SetAlign(expr.Result().Function.Returns.Align(c.target))
 
 				value = pointer
 			}
@@ -728,6 +857,44 @@ func (c *codegen) VisitMember(expr *ast.Member) {
 
 // Utils
 
+// embeddedPointer returns a pointer to the embedded struct at the end of the path of embedded fields.
+func (c *codegen) embeddedPointer(pointer llvm.Value, struct_ types.Type, path []*ast.Field, location scanner.Token) llvm.Value {
+	i32Type_ := types.PrimitiveType{Kind: types.I32}
+	i32Type := c.getType(&i32Type_)
+
+	indices := []llvm.Value{c.function.Literal(i32Type, llvm.Literal{Signed: 0})}
+	s := struct_.(*ast.Struct)
+
+	for _, field := range path {
+		i, _ := s.GetField(field.Name.Lexeme)
+		indices = append(indices, c.function.Literal(i32Type, llvm.Literal{Signed: int64(i)}))
+
+		s = field.Type.(*ast.Struct)
+	}
+
+	t := types.PointerType{Pointee: s}
+
+	result := c.block.GetElementPtr(pointer, indices, c.getType(&t), c.getType(struct_))
+	result.SetLocation(location)
+
+	return result
+}
+
+// embeddedPath returns the embedded fields leading from the pointee of one pointer to the pointee of the other.
+func embeddedPath(from, to *types.PointerType) []*ast.Field {
+	fromStruct, ok := from.Pointee.(*ast.Struct)
+	if !ok {
+		return nil
+	}
+
+	toStruct, ok := to.Pointee.(*ast.Struct)
+	if !ok {
+		return nil
+	}
+
+	return fromStruct.GetEmbeddedPath(toStruct)
+}
+
 func (c *codegen) binary(op scanner.Token, left exprValue, right exprValue, type_ types.Type) exprValue {
 	left = c.load(left, type_)
 	right = c.load(right, type_)
@@ -735,11 +902,11 @@ func (c *codegen) binary(op scanner.Token, left exprValue, right exprValue, type
 	var kind llvm.BinaryKind
 
 	switch op.Kind {
-	case scanner.Plus, scanner.PlusEqual, scanner.PlusPlus:
+	case scanner.Plus, scanner.PlusEqual, scanner.PlusPlus, scanner.PlusPercentage, scanner.PlusPercentageEqual:
 		kind = llvm.Add
-	case scanner.Minus, scanner.MinusEqual, scanner.MinusMinus:
+	case scanner.Minus, scanner.MinusEqual, scanner.MinusMinus, scanner.MinusPercentage, scanner.MinusPercentageEqual:
 		kind = llvm.Sub
-	case scanner.Star, scanner.StarEqual:
+	case scanner.Star, scanner.StarEqual, scanner.StarPercentage, scanner.StarPercentageEqual:
 		kind = llvm.Mul
 	case scanner.Slash, scanner.SlashEqual:
 		kind = llvm.Div
@@ -775,6 +942,19 @@ func (c *codegen) binary(op scanner.Token, left exprValue, right exprValue, type
 		panic("codegen.binary() - Invalid operator kind")
 	}
 
+	// Checked arithmetic
+	if v, ok := type_.(*types.PrimitiveType); ok && types.IsInteger(v.Kind) {
+		switch kind {
+		case llvm.Add, llvm.Sub, llvm.Mul:
+			if c.overflowChecks() && !scanner.IsWrapping(op.Kind) {
+				return exprValue{v: c.checkedBinary(kind, left.v, right.v, v, op)}
+			}
+
+		case llvm.Div, llvm.Rem:
+			c.checkDivisor(right.v, op)
+		}
+	}
+
 	result := c.block.Binary(kind, left.v, right.v)
 	result.SetLocation(op)
 
@@ -30,11 +30,38 @@ func (c *codegen) VisitVariable(stmt *ast.Variable) {
 		initializer := c.loadExpr(stmt.Initializer)
 
 		store := c.block.Store(pointer.v, initializer.v)
-		store.SetAlign(stmt.Type.Align())
+		store.SetAlign(stmt.Type.Align(c.target))
 		store.SetLocation(stmt.Name)
 	}
 }
 
+func (c *codegen) VisitDestructure(stmt *ast.Destructure) {
+	initializer := c.loadExpr(stmt.Initializer)
+	s, _ := stmt.Initializer.Result().Type.(*ast.Struct)
+
+	for i, binding := range stmt.Bindings {
+		if binding, ok := binding.(*ast.Variable); ok {
+			// Variable
+			pointer := c.allocas[binding]
+			c.addVariable(binding.Name, pointer)
+
+			// Element
+			index := i
+
+			if s != nil {
+				index, _ = s.GetField(binding.Name.Lexeme)
+			}
+
+			value := c.block.ExtractValue(initializer.v, index)
+			value.SetLocation(binding.Name)
+
+			store := c.block.Store(pointer.v, value)
+			store.SetAlign(binding.Type.Align(c.target))
+			store.SetLocation(binding.Name)
+		}
+	}
+}
+
 func (c *codegen) VisitIf(stmt *ast.If) {
 	// Get blocks
 	then := c.function.Block("if.then")
@@ -131,3 +158,6 @@ func (c *codegen) VisitBreak(stmt *ast.Break) {
 func (c *codegen) VisitContinue(stmt *ast.Continue) {
 	c.block.Br(nil, c.loopStart, nil).SetLocation(stmt.Token())
 }
+
+func (c *codegen) VisitStaticAssertStmt(_ *ast.StaticAssertStmt) {
+}
@@ -0,0 +1,240 @@
+package deriver
+
+import (
+	"fireball/core"
+	"fireball/core/ast"
+	"fireball/core/scanner"
+	"fireball/core/types"
+	"fireball/core/utils"
+	"fmt"
+	"slices"
+)
+
+// Derive expands the 'Derive' attributes of structs into impls with the generated methods, the impls are appended to
+// the declarations. Must be called once the types of all files are resolved.
+func Derive(reporter utils.Reporter, resolver utils.Resolver, decls []ast.Decl) []ast.Decl {
+	derived := make([]ast.Decl, 0)
+
+	for _, decl := range decls {
+		struct_, ok := decl.(*ast.Struct)
+		if !ok {
+			continue
+		}
+
+		for _, attribute := range struct_.Attributes {
+			if _, ok := attribute.Value.(types.DeriveAttribute); !ok {
+				continue
+			}
+
+			d := &deriver{
+				reporter: reporter,
+				resolver: resolver,
+				struct_:  struct_,
+			}
+
+			if impl := d.derive(attribute); impl != nil {
+				derived = append(derived, impl)
+			}
+		}
+	}
+
+	return append(decls, derived...)
+}
+
+type trait struct {
+	name   string
+	method string
+	static bool
+
+	generate func(d *deriver) *ast.Func
+}
+
+var traits = []trait{
+	{name: "Eq", method: "equals", generate: (*deriver).equals},
+	{name: "Hash", method: "hash", generate: (*deriver).hash},
+	{name: "Default", method: "default", static: true, generate: (*deriver).default_},
+	{name: "Debug", method: "debug", generate: (*deriver).debug},
+}
+
+func getTrait(name string) *trait {
+	for i := range traits {
+		if traits[i].name == name {
+			return &traits[i]
+		}
+	}
+
+	return nil
+}
+
+type deriver struct {
+	reporter utils.Reporter
+	resolver utils.Resolver
+
+	struct_ *ast.Struct
+
+	// Trait being generated and its argument, all generated tokens are placed at the argument
+	trait *trait
+	arg   scanner.Token
+
+	// Set when a generated method calls printf
+	usesPrintf bool
+}
+
+func (d *deriver) derive(attribute ast.Attribute) *ast.Impl {
+	impl := &ast.Impl{
+		Struct:  d.struct_.Name,
+		Type_:   d.struct_,
+		Derived: true,
+	}
+
+	seen := utils.NewSet[string]()
+
+	for _, arg := range attribute.Args {
+		if arg.Kind != scanner.String {
+			// Reported when the attribute was resolved
+			continue
+		}
+
+		name, err := scanner.ParseString(arg)
+		if err != nil {
+			continue
+		}
+
+		trait := getTrait(name)
+
+		if trait == nil {
+			d.errorToken(arg, "Unknown derive trait '%s', expected one of 'Eq', 'Hash', 'Default' or 'Debug'.", name)
+			continue
+		}
+
+		if d.hasMethod(d.struct_, trait) {
+			d.errorToken(arg, "Struct '%s' already has a method named '%s'.", d.struct_.Name, trait.method)
+			continue
+		}
+
+		if seen.Contains(name) {
+			d.errorToken(arg, "Trait '%s' is derived multiple times.", name)
+			continue
+		}
+
+		seen.Add(name)
+
+		d.trait = trait
+		d.arg = arg
+
+		if !d.checkFields(trait) {
+			continue
+		}
+
+		function := trait.generate(d)
+		function.Docs = fmt.Sprintf("Derived from '%s'.", name)
+
+		// Derived methods only read 'this', so they can be called on immutable values
+		if !trait.static {
+			function.Flags |= ast.Const
+		}
+		function.SetRangeToken(arg, arg)
+
+		impl.Functions = append(impl.Functions, function)
+	}
+
+	if len(impl.Functions) == 0 {
+		return nil
+	}
+
+	if d.usesPrintf {
+		impl.Functions = append(impl.Functions, d.printfDecl())
+	}
+
+	impl.SetRangeToken(attribute.Args[0], attribute.Args[len(attribute.Args)-1])
+	parentSetter{}.AcceptDecl(impl)
+
+	return impl
+}
+
+// checkFields reports an error for every field of a struct type which does not implement the trait itself.
+func (d *deriver) checkFields(trait *trait) bool {
+	if trait.name == "Default" {
+		return true
+	}
+
+	valid := true
+
+	for _, field := range d.struct_.Fields {
+		struct_, ok := baseType(field.Type).(*ast.Struct)
+		if !ok || d.implements(struct_, trait) {
+			continue
+		}
+
+		if d.hasMethod(struct_, trait) {
+			d.errorToken(d.arg, "Field '%s' of type '%s' needs a const '%s' method to derive '%s'.", field.Name, struct_.Name, trait.method, trait.name)
+		} else {
+			d.errorToken(d.arg, "Field '%s' of type '%s' does not implement '%s'.", field.Name, struct_.Name, trait.name)
+		}
+
+		valid = false
+	}
+
+	return valid
+}
+
+// implements returns true if the struct derives the trait or has a hand-written method for it. Derived methods are
+// const, so they can only call hand-written methods which are const too.
+func (d *deriver) implements(struct_ *ast.Struct, trait *trait) bool {
+	var derive types.DeriveAttribute
+
+	if ast.GetAttribute(struct_, &derive) && slices.Contains(derive.Traits, trait.name) {
+		return true
+	}
+
+	method, _ := d.resolver.GetMethod(struct_, trait.method, trait.static)
+	return method != nil && (trait.static || method.IsConst())
+}
+
+// hasMethod returns true if the struct itself has a method with the name of the trait's method.
+func (d *deriver) hasMethod(struct_ *ast.Struct, trait *trait) bool {
+	method, _ := d.resolver.GetMethod(struct_, trait.method, trait.static)
+	return method != nil
+}
+
+func (d *deriver) errorToken(token scanner.Token, format string, args ...any) {
+	d.reporter.Report(utils.Diagnostic{
+		Kind:    utils.ErrorKind,
+		Range:   core.TokenToRange(token),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// baseType returns the element type of (nested) arrays or the type itself.
+func baseType(type_ types.Type) types.Type {
+	for {
+		array, ok := type_.(*types.ArrayType)
+		if !ok {
+			return type_
+		}
+
+		type_ = array.Base
+	}
+}
+
+// parentSetter sets the parent of all nodes in a generated tree.
+type parentSetter struct{}
+
+type parented interface {
+	SetChildrenParent()
+}
+
+func (p parentSetter) AcceptDecl(decl ast.Decl) {
+	decl.(parented).SetChildrenParent()
+	decl.AcceptChildren(p)
+}
+
+func (p parentSetter) AcceptStmt(stmt ast.Stmt) {
+	stmt.(parented).SetChildrenParent()
+	stmt.AcceptChildren(p)
+}
+
+func (p parentSetter) AcceptExpr(expr ast.Expr) {
+	expr.(parented).SetChildrenParent()
+	expr.AcceptChildren(p)
+}
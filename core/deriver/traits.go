@@ -0,0 +1,352 @@
+package deriver
+
+import (
+	"fireball/core"
+	"fireball/core/ast"
+	"fireball/core/scanner"
+	"fireball/core/types"
+	"fmt"
+)
+
+// Eq
+
+// equals generates 'equals(other T) bool' comparing all fields.
+func (d *deriver) equals() *ast.Func {
+	body := make([]ast.Stmt, 0, len(d.struct_.Fields)+1)
+
+	for _, field := range d.struct_.Fields {
+		name := field.Name.Lexeme
+		body = append(body, d.compare(d.member(d.this(), name), d.member(d.identifier("other"), name), field.Type, 0)...)
+	}
+
+	body = append(body, d.return_(d.literal(scanner.True, "true")))
+
+	params := []ast.Param{{Name: d.token(scanner.Identifier, "other"), Type: d.struct_}}
+	return d.method("equals", params, primitive(types.Bool), body)
+}
+
+func (d *deriver) compare(left, right ast.Expr, type_ types.Type, depth int) []ast.Stmt {
+	var condition ast.Expr
+
+	switch type_ := type_.(type) {
+	case *types.ArrayType:
+		i := loopVariable(depth)
+
+		return []ast.Stmt{d.loop(i, type_.Count, d.compare(d.index(left, i), d.index(right, i), type_.Base, depth+1)...)}
+
+	case *ast.Struct:
+		condition = d.not(d.call(d.member(left, "equals"), right))
+
+	default:
+		condition = d.binary(left, scanner.BangEqual, "!=", right)
+	}
+
+	return []ast.Stmt{d.if_(condition, d.block(d.return_(d.literal(scanner.False, "false"))), nil)}
+}
+
+// Hash
+
+// FNV-1a offset basis and prime
+const (
+	hashBasis = "14695981039346656037u64"
+	hashPrime = "1099511628211u64"
+)
+
+// hash generates 'hash() u64' combining the fields with FNV-1a.
+func (d *deriver) hash() *ast.Func {
+	body := make([]ast.Stmt, 0, len(d.struct_.Fields)+2)
+	body = append(body, d.variable("h", primitive(types.U64), d.literal(scanner.Number, hashBasis)))
+
+	for _, field := range d.struct_.Fields {
+		body = append(body, d.hashValue(d.member(d.this(), field.Name.Lexeme), field.Type, 0)...)
+	}
+
+	body = append(body, d.return_(d.identifier("h")))
+
+	return d.method("hash", nil, primitive(types.U64), body)
+}
+
+func (d *deriver) hashValue(value ast.Expr, type_ types.Type, depth int) []ast.Stmt {
+	var bits ast.Expr
+
+	switch type_ := type_.(type) {
+	case *types.ArrayType:
+		i := loopVariable(depth)
+
+		return []ast.Stmt{d.loop(i, type_.Count, d.hashValue(d.index(value, i), type_.Base, depth+1)...)}
+
+	case *ast.Struct:
+		bits = d.call(d.member(value, "hash"))
+
+	case *ast.Enum:
+		bits = d.cast(value, primitive(types.U64))
+
+	case *types.PrimitiveType:
+		if types.IsFloating(type_.Kind) {
+			bits = d.reinterpret(value, type_.Size(d.resolver.GetTarget()))
+		} else {
+			bits = d.cast(value, primitive(types.U64))
+		}
+
+	default:
+		// Pointers and functions are hashed by their address
+		bits = d.reinterpret(value, type_.Size(d.resolver.GetTarget()))
+	}
+
+	// h = (h ^ bits) *% prime
+	combined := d.binary(
+		&ast.Group{Token_: d.token(scanner.LeftParen, "("), Expr: d.binary(d.identifier("h"), scanner.Xor, "^", bits)},
+		scanner.StarPercentage, "*%",
+		d.literal(scanner.Number, hashPrime),
+	)
+
+	return []ast.Stmt{d.expression(&ast.Assignment{Assignee: d.identifier("h"), Op: d.token(scanner.Equal, "="), Value: combined})}
+}
+
+// reinterpret reads the bits of an addressable value as an unsigned integer of the same size, widened to u64.
+func (d *deriver) reinterpret(value ast.Expr, size int) ast.Expr {
+	kind := types.U64
+	if size == 4 {
+		kind = types.U32
+	}
+
+	pointer := d.cast(&ast.Unary{Op: d.token(scanner.Ampersand, "&"), Value: value, Prefix: true}, &types.PointerType{Pointee: primitive(kind)})
+	bits := ast.Expr(&ast.Unary{Op: d.token(scanner.Star, "*"), Value: pointer, Prefix: true})
+
+	if kind != types.U64 {
+		bits = d.cast(bits, primitive(types.U64))
+	}
+
+	return bits
+}
+
+// Default
+
+// default_ generates 'static default() T', fields of structs with a 'default' method use it and the rest are zero.
+func (d *deriver) default_() *ast.Func {
+	initializer := &ast.StructInitializer{
+		Token_: d.token(scanner.Identifier, d.struct_.Name.Lexeme),
+		Target: d.struct_,
+	}
+
+	for _, field := range d.struct_.Fields {
+		if struct_, ok := field.Type.(*ast.Struct); ok && d.implements(struct_, d.trait) {
+			initializer.Fields = append(initializer.Fields, ast.InitField{
+				Name:  d.token(scanner.Identifier, field.Name.Lexeme),
+				Value: d.call(d.member(d.identifier(struct_.Name.Lexeme), "default")),
+			})
+		}
+	}
+
+	function := d.method("default", nil, d.struct_, []ast.Stmt{d.return_(initializer)})
+	function.Flags |= ast.Static
+
+	return function
+}
+
+// Debug
+
+// debug generates 'debug()' printing the struct like 'Name { a: 1, b: 2 }' without a new line.
+func (d *deriver) debug() *ast.Func {
+	body := make([]ast.Stmt, 0, len(d.struct_.Fields)*2+2)
+
+	if len(d.struct_.Fields) == 0 {
+		body = append(body, d.print(d.struct_.Name.Lexeme+" {}"))
+	} else {
+		for i, field := range d.struct_.Fields {
+			prefix := ", "
+			if i == 0 {
+				prefix = d.struct_.Name.Lexeme + " { "
+			}
+
+			body = append(body, d.print(prefix+field.Name.Lexeme+": "))
+			name := field.Name.Lexeme
+			value := func() ast.Expr { return d.member(d.this(), name) }
+
+			body = append(body, d.debugValue(value, field.Type, 0)...)
+		}
+
+		body = append(body, d.print(" }"))
+	}
+
+	return d.method("debug", nil, primitive(types.Void), body)
+}
+
+// debugValue prints a value, which is created with the function because the enum case comparisons need it multiple
+// times.
+func (d *deriver) debugValue(value func() ast.Expr, type_ types.Type, depth int) []ast.Stmt {
+	switch type_ := type_.(type) {
+	case *types.ArrayType:
+		i := loopVariable(depth)
+
+		separator := d.if_(d.binary(d.identifier(i), scanner.Greater, ">", d.literal(scanner.Number, "0")), d.block(d.print(", ")), nil)
+		element := func() ast.Expr { return d.index(value(), i) }
+		body := append([]ast.Stmt{separator}, d.debugValue(element, type_.Base, depth+1)...)
+
+		return []ast.Stmt{
+			d.print("["),
+			d.loop(i, type_.Count, body...),
+			d.print("]"),
+		}
+
+	case *ast.Struct:
+		return []ast.Stmt{d.expression(d.call(d.member(value(), "debug")))}
+
+	case *ast.Enum:
+		// Prints the name of the case or the value if it does not match any
+		stmt := d.debugValue(func() ast.Expr { return d.cast(value(), type_.Type) }, type_.Type, depth)[0]
+
+		for i := len(type_.Cases) - 1; i >= 0; i-- {
+			case_ := type_.Cases[i].Name.Lexeme
+			condition := d.binary(value(), scanner.EqualEqual, "==", d.member(d.identifier(type_.Name.Lexeme), case_))
+
+			stmt = d.if_(condition, d.block(d.print(case_)), stmt)
+		}
+
+		return []ast.Stmt{stmt}
+
+	case *types.PrimitiveType:
+		switch {
+		case type_.Kind == types.Bool:
+			return []ast.Stmt{d.if_(value(), d.block(d.print("true")), d.block(d.print("false")))}
+
+		case types.IsFloating(type_.Kind):
+			return []ast.Stmt{d.printf("%g", d.cast(value(), primitive(types.F64)))}
+
+		case types.IsSigned(type_.Kind):
+			return []ast.Stmt{d.printf("%lld", d.cast(value(), primitive(types.I64)))}
+
+		case types.IsUnsigned(type_.Kind):
+			return []ast.Stmt{d.printf("%llu", d.cast(value(), primitive(types.U64)))}
+		}
+	}
+
+	// Pointers and functions
+	return []ast.Stmt{d.printf("%p", value())}
+}
+
+// print prints text which must not contain format specifiers.
+func (d *deriver) print(text string) ast.Stmt {
+	return d.printf(text)
+}
+
+func (d *deriver) printf(format string, args ...ast.Expr) ast.Stmt {
+	d.usesPrintf = true
+
+	callee := d.member(d.identifier(d.struct_.Name.Lexeme), "_printf")
+	args = append([]ast.Expr{d.literal(scanner.String, fmt.Sprintf("\"%s\"", format))}, args...)
+
+	return d.expression(d.call(callee, args...))
+}
+
+// printfDecl declares the printf of the target as a private static method of the derived impl.
+func (d *deriver) printfDecl() *ast.Func {
+	extern := ast.Attribute{
+		Name:  d.token(scanner.Identifier, "Extern"),
+		Args:  []scanner.Token{d.token(scanner.String, "\"printf\"")},
+		Value: types.ExternAttribute{Name: "printf"},
+	}
+
+	return &ast.Func{
+		Attributes: []ast.Attribute{extern},
+		Flags:      ast.Static | ast.Variadic,
+		Name:       d.token(scanner.Identifier, "_printf"),
+		Params:     []ast.Param{{Name: d.token(scanner.Identifier, "format"), Type: &types.PointerType{Pointee: primitive(types.U8)}}},
+		Returns:    primitive(types.I32),
+	}
+}
+
+// Nodes, all tokens are placed at the argument of the trait while the ranges of the nodes are left empty
+
+func (d *deriver) token(kind scanner.TokenKind, lexeme string) scanner.Token {
+	token := d.arg
+	token.Kind = kind
+	token.Lexeme = lexeme
+
+	return token
+}
+
+func (d *deriver) method(name string, params []ast.Param, returns types.Type, body []ast.Stmt) *ast.Func {
+	return &ast.Func{
+		Pub:     true,
+		Name:    d.token(scanner.Identifier, name),
+		Params:  params,
+		Returns: returns,
+		Body:    body,
+	}
+}
+
+func (d *deriver) block(stmts ...ast.Stmt) *ast.Block {
+	return &ast.Block{Token_: d.token(scanner.LeftBrace, "{"), Stmts: stmts}
+}
+
+func (d *deriver) expression(expr ast.Expr) *ast.Expression {
+	return &ast.Expression{Token_: expr.Token(), Expr: expr}
+}
+
+func (d *deriver) variable(name string, type_ types.Type, initializer ast.Expr) *ast.Variable {
+	return &ast.Variable{Type: type_, Name: d.token(scanner.Identifier, name), Initializer: initializer}
+}
+
+func (d *deriver) if_(condition ast.Expr, then ast.Stmt, else_ ast.Stmt) *ast.If {
+	return &ast.If{Token_: d.token(scanner.If, "if"), Condition: condition, Then: then, Else: else_}
+}
+
+// loop generates 'for (var i u32 = 0; i < count; i++) { body }'.
+func (d *deriver) loop(name string, count uint32, body ...ast.Stmt) *ast.For {
+	return &ast.For{
+		Token_:      d.token(scanner.For, "for"),
+		Initializer: d.variable(name, primitive(types.U32), d.literal(scanner.Number, "0")),
+		Condition:   d.binary(d.identifier(name), scanner.Less, "<", d.literal(scanner.Number, fmt.Sprint(count))),
+		Increment:   &ast.Unary{Op: d.token(scanner.PlusPlus, "++"), Value: d.identifier(name)},
+		Body:        d.block(body...),
+	}
+}
+
+func (d *deriver) return_(expr ast.Expr) *ast.Return {
+	return &ast.Return{Token_: d.token(scanner.Return, "return"), Expr: expr}
+}
+
+func (d *deriver) literal(kind scanner.TokenKind, lexeme string) *ast.Literal {
+	return &ast.Literal{Value: d.token(kind, lexeme)}
+}
+
+func (d *deriver) identifier(name string) *ast.Identifier {
+	return &ast.Identifier{Identifier: d.token(scanner.Identifier, name)}
+}
+
+func (d *deriver) this() *ast.Identifier {
+	return d.identifier("this")
+}
+
+func (d *deriver) member(value ast.Expr, name string) *ast.Member {
+	return &ast.Member{Value: value, Name: d.token(scanner.Identifier, name)}
+}
+
+func (d *deriver) index(value ast.Expr, name string) *ast.Index {
+	return &ast.Index{Token_: d.token(scanner.LeftBracket, "["), Value: value, Index: d.identifier(name)}
+}
+
+func (d *deriver) call(callee ast.Expr, args ...ast.Expr) *ast.Call {
+	return &ast.Call{Token_: d.token(scanner.LeftParen, "("), Callee: callee, Args: args}
+}
+
+func (d *deriver) binary(left ast.Expr, op scanner.TokenKind, lexeme string, right ast.Expr) *ast.Binary {
+	return &ast.Binary{Left: left, Op: d.token(op, lexeme), Right: right}
+}
+
+func (d *deriver) not(value ast.Expr) *ast.Unary {
+	return &ast.Unary{Op: d.token(scanner.Bang, "!"), Value: value, Prefix: true}
+}
+
+func (d *deriver) cast(expr ast.Expr, target types.Type) *ast.Cast {
+	return &ast.Cast{Token_: d.token(scanner.As, "as"), Target: target, Expr: expr}
+}
+
+func loopVariable(depth int) string {
+	return fmt.Sprintf("_i%d", depth)
+}
+
+func primitive(kind types.PrimitiveKind) *types.PrimitiveType {
+	return types.Primitive(kind, core.Range{})
+}
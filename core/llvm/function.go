@@ -138,6 +138,18 @@ func (b *Block) Name() string {
 	return b.name
 }
 
+// Terminated returns true if the block contains a terminator, instructions added after it are not emitted.
+func (b *Block) Terminated() bool {
+	for _, inst := range b.instructions {
+		switch inst.(type) {
+		case *br, *ret, *unreachable:
+			return true
+		}
+	}
+
+	return false
+}
+
 func (b *Block) Variable(name string, pointer Value) Instruction {
 	i := &variableMetadata{
 		instruction: instruction{
@@ -291,7 +303,7 @@ func (b *Block) Alloca(type_ Type) AlignedInstructionValue {
 	i := &alloca{
 		instruction: instruction{
 			module:   b.module,
-			type_:    &pointerType{pointee: type_},
+			type_:    &pointerType{pointee: type_, size: b.module.pointerSize()},
 			location: -1,
 		},
 		type_: type_,
@@ -315,6 +327,14 @@ func (b *Block) Load(pointer Value) AlignedInstructionValue {
 	return i
 }
 
+// VolatileLoad loads a value the optimizer can't assume anything about.
+func (b *Block) VolatileLoad(pointer Value) AlignedInstructionValue {
+	i := b.Load(pointer).(*load)
+	i.volatile = true
+
+	return i
+}
+
 func (b *Block) Store(pointer Value, value Value) AlignedInstruction {
 	i := &store{
 		instruction: instruction{
@@ -404,3 +424,15 @@ func (b *Block) Ret(value Value) Instruction {
 	b.instructions = append(b.instructions, i)
 	return i
 }
+
+func (b *Block) Unreachable() Instruction {
+	i := &unreachable{
+		instruction: instruction{
+			module:   b.module,
+			location: -1,
+		},
+	}
+
+	b.instructions = append(b.instructions, i)
+	return i
+}
@@ -140,8 +140,9 @@ func (a *alloca) SetAlign(align int) {
 
 type load struct {
 	instruction
-	pointer Value
-	align   int
+	pointer  Value
+	align    int
+	volatile bool
 }
 
 func (l *load) SetAlign(align int) {
@@ -191,3 +192,7 @@ type ret struct {
 	instruction
 	value Value
 }
+
+type unreachable struct {
+	instruction
+}
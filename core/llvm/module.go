@@ -1,5 +1,7 @@
 package llvm
 
+import "fireball/core/target"
+
 type Location interface {
 	Line() int
 	Column() int
@@ -7,6 +9,7 @@ type Location interface {
 
 type Module struct {
 	source string
+	target target.Target
 
 	types []Type
 
@@ -16,6 +19,7 @@ type Module struct {
 	variables []*variable
 
 	declares []*functionType
+	declared map[string]*declare
 	defines  []*Function
 
 	namedMetadata map[string]Metadata
@@ -23,15 +27,24 @@ type Module struct {
 
 	typeMetadata map[Type]int
 	scopes       []int
+
+	stripDebugInfo bool
 }
 
-func NewModule() *Module {
+func NewModule(t target.Target) *Module {
 	return &Module{
+		target:        t,
+		declared:      make(map[string]*declare),
 		namedMetadata: make(map[string]Metadata),
 		typeMetadata:  make(map[Type]int),
 	}
 }
 
+// pointerSize returns the size of pointers in bits.
+func (m *Module) pointerSize() int {
+	return m.target.PointerSize() * 8
+}
+
 func (m *Module) Source(path string) {
 	m.source = path
 	producer := "fireball version 0.1.0"
@@ -58,6 +71,12 @@ func (m *Module) Source(path string) {
 	}}
 }
 
+// StripDebugInfo makes the module be written without debug info. Scopes and locations are still tracked but only the
+// module flags and identification are emitted.
+func (m *Module) StripDebugInfo() {
+	m.stripDebugInfo = true
+}
+
 // Functions
 
 type declare struct {
@@ -78,12 +97,22 @@ func (d *declare) Name() string {
 }
 
 func (m *Module) Declare(type_ Type) Value {
-	m.declares = append(m.declares, type_.(*functionType))
+	t := type_.(*functionType)
 
-	return &declare{
+	// The same external function can be declared multiple times, only the first declaration is emitted
+	if existing, ok := m.declared[t.name]; ok {
+		return existing
+	}
+
+	m.declares = append(m.declares, t)
+
+	value := &declare{
 		type_: type_,
-		name:  type_.(*functionType).name,
+		name:  t.name,
 	}
+
+	m.declared[t.name] = value
+	return value
 }
 
 func (m *Module) Define(type_ Type, debugName string) *Function {
@@ -223,6 +252,7 @@ func (m *Module) Pointer(name string, pointee Type) Type {
 	t := &pointerType{
 		name:    name,
 		pointee: pointee,
+		size:    m.pointerSize(),
 	}
 
 	m.typeMetadata[t] = m.addMetadata(Metadata{
@@ -253,18 +283,15 @@ func (m *Module) Function(name string, parameters []Type, variadic bool, returns
 		parameters: parameters,
 		variadic:   variadic,
 		returns:    returns,
+		size:       m.pointerSize(),
 	}
 
 	types := make([]MetadataField, len(parameters)+1)
 
-	if _, ok := returns.(*voidType); ok {
-		types[0] = MetadataField{Value: enumMetadataValue("null")}
-	} else {
-		types[0] = MetadataField{Value: refMetadataValue(m.typeMetadata[returns])}
-	}
+	types[0] = MetadataField{Value: m.typeMetadataValue(returns)}
 
 	for i, parameter := range parameters {
-		types[i+1] = MetadataField{Value: refMetadataValue(m.typeMetadata[parameter])}
+		types[i+1] = MetadataField{Value: m.typeMetadataValue(parameter)}
 	}
 
 	m.typeMetadata[t] = m.addMetadata(Metadata{
@@ -357,6 +384,19 @@ func (m *Module) Struct(name string, size int, fields []Field) Type {
 	return t
 }
 
+func (m *Module) LiteralStruct(fields []Type) Type {
+	t := &structType{
+		fields: make([]Field, len(fields)),
+	}
+
+	for i, field := range fields {
+		t.fields[i] = Field{Type: field}
+		t.size += field.Size()
+	}
+
+	return t
+}
+
 func (m *Module) Alias(name string, underlying Type) Type {
 	t := &aliasType{
 		name:       name,
@@ -507,6 +547,14 @@ func (m *Module) getCompileUnit() int {
 	return 1
 }
 
+func (m *Module) typeMetadataValue(type_ Type) MetadataValue {
+	if metadata, ok := m.typeMetadata[type_]; ok {
+		return refMetadataValue(metadata)
+	}
+
+	return enumMetadataValue("null")
+}
+
 func (m *Module) addFlagMetadata(num1 int, str string, num2 int) int {
 	return m.addMetadata(Metadata{Fields: []MetadataField{
 		{Value: numberMetadataValue(num1)},
@@ -24,6 +24,8 @@ type textWriter struct {
 	globalValueNamesCount map[string]int
 
 	metadataCount int
+
+	stripDebugInfo bool
 }
 
 func WriteText(module *Module, writer io.Writer) {
@@ -36,10 +38,14 @@ func WriteText(module *Module, writer io.Writer) {
 
 		globalValueNames:      make(map[Value]string),
 		globalValueNamesCount: make(map[string]int),
+
+		stripDebugInfo: module.stripDebugInfo,
 	}
 
 	// Source
 	w.fmt("source_filename = \"%s\"\n", module.source)
+	w.fmt("target datalayout = \"%s\"\n", module.target.DataLayout())
+	w.fmt("target triple = \"%s\"\n", module.target.Triple())
 	w.line()
 
 	// Types
@@ -114,12 +120,18 @@ func WriteText(module *Module, writer io.Writer) {
 			}
 		}
 
+		w.raw(")")
+
 		if define.alwaysInline {
-			w.fmt(") alwaysinline !dbg !%d {\n", define.metadata)
-		} else {
-			w.fmt(") !dbg !%d {\n", define.metadata)
+			w.raw(" alwaysinline")
 		}
 
+		if !w.stripDebugInfo {
+			w.fmt(" !dbg !%d", define.metadata)
+		}
+
+		w.raw(" {\n")
+
 		w.body(define.blocks)
 		w.raw("}\n\n")
 	}
@@ -190,6 +202,10 @@ func (w *textWriter) body(blocks []*Block) {
 		w.fmt("%s:\n", w.value(block)[1:])
 
 		for _, inst := range block.instructions {
+			if _, ok := inst.(*variableMetadata); ok && w.stripDebugInfo {
+				continue
+			}
+
 			w.raw("    ")
 
 			if w.instruction(inst) {
@@ -249,13 +265,13 @@ func (w *textWriter) instruction(i Value) bool {
 		case Ne:
 			a = ternary(isFloating(inst.left.Type()), "fcmp one", "icmp ne")
 		case Lt:
-			a = ternary(isFloating(inst.left.Type()), "fcmp olt", ternary(isSigned(inst.Type()), "icmp slt", "icmp ult"))
+			a = ternary(isFloating(inst.left.Type()), "fcmp olt", ternary(isSigned(inst.left.Type()), "icmp slt", "icmp ult"))
 		case Le:
-			a = ternary(isFloating(inst.left.Type()), "fcmp ole", ternary(isSigned(inst.Type()), "icmp sle", "icmp ule"))
+			a = ternary(isFloating(inst.left.Type()), "fcmp ole", ternary(isSigned(inst.left.Type()), "icmp sle", "icmp ule"))
 		case Gt:
-			a = ternary(isFloating(inst.left.Type()), "fcmp ogt", ternary(isSigned(inst.Type()), "icmp sgt", "icmp ugt"))
+			a = ternary(isFloating(inst.left.Type()), "fcmp ogt", ternary(isSigned(inst.left.Type()), "icmp sgt", "icmp ugt"))
 		case Ge:
-			a = ternary(isFloating(inst.left.Type()), "fcmp oge", ternary(isSigned(inst.Type()), "icmp sge", "icmp uge"))
+			a = ternary(isFloating(inst.left.Type()), "fcmp oge", ternary(isSigned(inst.left.Type()), "icmp sge", "icmp uge"))
 
 		case Or:
 			a = "or"
@@ -323,10 +339,16 @@ func (w *textWriter) instruction(i Value) bool {
 		location = inst.location
 
 	case *load:
+		if inst.volatile {
+			w.raw("load volatile ")
+		} else {
+			w.raw("load ")
+		}
+
 		if inst.align != 0 {
-			w.fmt("load %s, ptr %s, align %d", w.type_(inst.Type()), w.value(inst.pointer), inst.align)
+			w.fmt("%s, ptr %s, align %d", w.type_(inst.Type()), w.value(inst.pointer), inst.align)
 		} else {
-			w.fmt("load %s, ptr %s", w.type_(inst.Type()), w.value(inst.pointer))
+			w.fmt("%s, ptr %s", w.type_(inst.Type()), w.value(inst.pointer))
 		}
 
 		location = inst.location
@@ -363,7 +385,18 @@ func (w *textWriter) instruction(i Value) bool {
 		location = inst.location
 
 	case *call:
-		w.fmt("call %s %s(", w.type_(inst.Type()), w.value(inst.value))
+		if function, ok := inst.value.Type().(*functionType); ok && function.variadic {
+			// Variadic calls need the full function type so the arguments are passed correctly
+			w.fmt("call %s (", w.type_(inst.Type()))
+
+			for _, parameter := range function.parameters {
+				w.fmt("%s, ", w.type_(parameter))
+			}
+
+			w.fmt("...) %s(", w.value(inst.value))
+		} else {
+			w.fmt("call %s %s(", w.type_(inst.Type()), w.value(inst.value))
+		}
 
 		for i, argument := range inst.arguments {
 			if i > 0 {
@@ -376,6 +409,10 @@ func (w *textWriter) instruction(i Value) bool {
 		w.raw(")")
 		location = inst.location
 
+	case *unreachable:
+		w.raw("unreachable")
+		terminal = true
+
 	case *ret:
 		if inst.value == nil {
 			w.raw("ret void")
@@ -390,7 +427,7 @@ func (w *textWriter) instruction(i Value) bool {
 		panic("textWriter.instruction() - Invalid instruction")
 	}
 
-	if location != -1 {
+	if location != -1 && !w.stripDebugInfo {
 		w.fmt(", !dbg !%d", location)
 	}
 
@@ -435,6 +472,19 @@ func (w *textWriter) type_(type_ Type) string {
 	} else if _, ok := type_.(*functionType); ok {
 		// Function
 		name = "ptr"
+	} else if v, ok := type_.(*structType); ok {
+		// Literal struct
+		name = "{ "
+
+		for i, field := range v.fields {
+			if i > 0 {
+				name += ", "
+			}
+
+			name += w.type_(field.Type)
+		}
+
+		name += " }"
 	} else if v, ok := type_.(*aliasType); ok {
 		// Alias
 		name = w.type_(v.underlying)
@@ -450,42 +500,17 @@ func (w *textWriter) type_(type_ Type) string {
 
 // Constants
 
+// escape converts raw bytes into a null terminated LLVM string constant, returns the escaped text and its length.
 func escape(original string) (string, int) {
 	data := make([]uint8, 0, len(original)+3)
-	length := 1
 
 	for i := 0; i < len(original); i++ {
 		char := original[i]
 
-		if char == '\\' {
-			i++
-
-			switch original[i] {
-			case '0':
-				data = append(data, '\\')
-				data = append(data, '0')
-				data = append(data, '0')
-
-			case 'n':
-				data = append(data, '\\')
-				data = append(data, '0')
-				data = append(data, 'A')
-
-			case 'r':
-				data = append(data, '\\')
-				data = append(data, '0')
-				data = append(data, 'D')
-
-			case 't':
-				data = append(data, '\\')
-				data = append(data, '0')
-				data = append(data, '9')
-			}
-
-			length++
+		if char < ' ' || char > '~' || char == '"' || char == '\\' {
+			data = append(data, fmt.Sprintf("\\%02X", char)...)
 		} else {
 			data = append(data, char)
-			length++
 		}
 	}
 
@@ -493,7 +518,7 @@ func escape(original string) (string, int) {
 	data = append(data, '0')
 	data = append(data, '0')
 
-	return string(data), length
+	return string(data), len(original) + 1
 }
 
 // Names
@@ -565,16 +590,32 @@ func surroundName(name string) string {
 // Debug
 
 func (w *textWriter) debug(module *Module) {
-	// Named metadata
+	// Named metadata, without debug info the compile unit is left out
+	referenced := utils.NewSet[int]()
+
 	for name, metadata := range module.namedMetadata {
+		if w.stripDebugInfo && name == "llvm.dbg.cu" {
+			continue
+		}
+
 		w.fmt("!%s = ", name)
 		w.metadata(metadata)
+
+		for _, field := range metadata.Fields {
+			if field.Value.Kind == RefMetadataValueKind {
+				referenced.Add(field.Value.Number)
+			}
+		}
 	}
 
 	w.line()
 
-	// Unnamed metadata
+	// Unnamed metadata, without debug info only the nodes of the named metadata are needed
 	for i, metadata := range module.metadata {
+		if w.stripDebugInfo && !referenced.Contains(i) {
+			continue
+		}
+
 		w.fmt("!%d = ", i)
 		w.metadata(metadata)
 	}
@@ -51,12 +51,13 @@ func (v *arrayType) Size() int {
 type pointerType struct {
 	name    string
 	pointee Type
+	size    int
 }
 
 func (v *pointerType) isType() {}
 
 func (v *pointerType) Size() int {
-	return 64
+	return v.size
 }
 
 type functionType struct {
@@ -64,12 +65,13 @@ type functionType struct {
 	parameters []Type
 	variadic   bool
 	returns    Type
+	size       int
 }
 
 func (v *functionType) isType() {}
 
 func (v *functionType) Size() int {
-	return 64
+	return v.size
 }
 
 type Field struct {
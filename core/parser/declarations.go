@@ -5,45 +5,40 @@ import (
 	"fireball/core/ast"
 	"fireball/core/scanner"
 	"fireball/core/types"
-	"strconv"
 )
 
 func (p *parser) declaration() ast.Decl {
-	attributesStart := p.next
-	attributes := p.parseAttributes()
+	docs, attributes := p.parseDocsAndAttributes()
 	start := p.next
+	pub := p.match(scanner.Pub)
 
 	if p.match(scanner.Struct) {
-		if len(attributes) > 0 {
-			p.error(attributesStart, "Structs cannot have attributes.")
-		}
+		return p.struct_(docs, attributes, pub)
+	}
 
-		return p.struct_()
+	if pub && (p.check(scanner.Impl) || (p.check(scanner.Identifier) && p.next.Lexeme == "static_assert")) {
+		p.error(start, "Only structs, enums, functions, fields and methods can be public.")
 	}
 
 	if p.match(scanner.Impl) {
-		if len(attributes) > 0 {
-			p.error(attributesStart, "Implementations cannot have attributes.")
-		}
-
-		return p.impl()
+		return p.impl(attributes)
 	}
 
 	if p.match(scanner.Enum) {
-		if len(attributes) > 0 {
-			p.error(attributesStart, "Enums cannot have attributes.")
-		}
-
-		return p.enum()
+		return p.enum(docs, attributes, pub)
 	}
 
 	if p.match(scanner.Func) {
-		return p.function(start, attributes, 0)
+		return p.function(start, docs, attributes, pub, 0)
+	}
+
+	if p.check(scanner.Identifier) && p.next.Lexeme == "static_assert" {
+		return p.staticAssertDecl(attributes)
 	}
 
 	if p.match(scanner.Static) {
 		if p.match(scanner.Func) {
-			return p.function(start, attributes, ast.Static)
+			return p.function(start, docs, attributes, pub, ast.Static)
 		}
 	}
 
@@ -54,7 +49,7 @@ func (p *parser) declaration() ast.Decl {
 	return nil
 }
 
-func (p *parser) struct_() ast.Decl {
+func (p *parser) struct_(docs string, attributes []ast.Attribute, pub bool) ast.Decl {
 	start := p.current
 
 	// Name
@@ -75,7 +70,13 @@ func (p *parser) struct_() ast.Decl {
 	staticFields := make([]ast.Field, 0)
 	fields := make([]ast.Field, 0, 4)
 
-	for p.canLoopAdvanced(scanner.RightBrace, scanner.Static, scanner.Identifier) {
+	for p.canLoopAdvanced(scanner.RightBrace, scanner.Hashtag, scanner.Pub, scanner.Static, scanner.Identifier) {
+		// Docs and attributes
+		docs, attributes := p.parseDocsAndAttributes()
+
+		// Pub
+		pub := p.match(scanner.Pub)
+
 		// Static
 		static := false
 
@@ -93,8 +94,17 @@ func (p *parser) struct_() ast.Decl {
 			continue
 		}
 
+		// Embedded struct, the name is the type
+		embedded := !static && (p.check(scanner.Comma) || p.check(scanner.RightBrace))
+
 		// Type
-		type_ := p.parseType()
+		var type_ types.Type
+
+		if embedded {
+			type_ = identifierType(name)
+		} else {
+			type_ = p.parseType()
+		}
 
 		if type_ == nil {
 			if !p.syncBeforeFieldOrDecl() {
@@ -105,8 +115,12 @@ func (p *parser) struct_() ast.Decl {
 
 		// Add
 		field := ast.Field{
-			Name: name,
-			Type: type_,
+			Docs:       docs,
+			Attributes: attributes,
+			Pub:        pub,
+			Embedded:   embedded,
+			Name:       name,
+			Type:       type_,
 		}
 
 		if static {
@@ -135,6 +149,9 @@ func (p *parser) struct_() ast.Decl {
 
 	// Return
 	decl := &ast.Struct{
+		Attributes:   attributes,
+		Docs:         docs,
+		Pub:          pub,
 		Name:         name,
 		StaticFields: staticFields,
 		Fields:       fields,
@@ -154,11 +171,11 @@ func (p *parser) struct_() ast.Decl {
 	return decl
 }
 
-func (p *parser) impl() ast.Decl {
+func (p *parser) impl(attributes []ast.Attribute) ast.Decl {
 	start := p.current
 
 	// Name
-	struct_ := p.consume(scanner.Identifier, "Expected struct name.")
+	struct_ := p.qualified(p.consume(scanner.Identifier, "Expected struct name."))
 
 	if struct_.IsError() {
 		p.syncToDecl()
@@ -174,21 +191,24 @@ func (p *parser) impl() ast.Decl {
 	// Functions
 	functions := make([]ast.Decl, 0, 8)
 
-	for p.canLoopAdvanced(scanner.RightBrace, scanner.Hashtag, scanner.Static, scanner.Func) {
+	for p.canLoopAdvanced(scanner.RightBrace, scanner.Hashtag, scanner.Pub, scanner.Static, scanner.Const, scanner.Func) {
 		start := p.next
 
-		attributes := p.parseAttributes()
+		docs, attributes := p.parseDocsAndAttributes()
+		pub := p.match(scanner.Pub)
 		flags := ast.FuncFlags(0)
 
 		if p.match(scanner.Static) {
 			flags = ast.Static
+		} else if p.match(scanner.Const) {
+			flags = ast.Const
 		}
 
 		if token := p.consume(scanner.Func, "Expected 'func' to start a function."); token.IsError() {
 			return nil
 		}
 
-		function := p.function(start, attributes, flags)
+		function := p.function(start, docs, attributes, pub, flags)
 		if function == nil {
 			p.syncToDecl()
 			return nil
@@ -204,8 +224,9 @@ func (p *parser) impl() ast.Decl {
 
 	// Return
 	decl := &ast.Impl{
-		Struct:    struct_,
-		Functions: functions,
+		Attributes: attributes,
+		Struct:     struct_,
+		Functions:  functions,
 	}
 
 	decl.SetRangeToken(start, p.current)
@@ -214,7 +235,7 @@ func (p *parser) impl() ast.Decl {
 	return decl
 }
 
-func (p *parser) enum() ast.Decl {
+func (p *parser) enum(docs string, attributes []ast.Attribute, pub bool) ast.Decl {
 	start := p.current
 
 	// Name
@@ -251,6 +272,7 @@ func (p *parser) enum() ast.Decl {
 
 	for p.canLoopAdvanced(scanner.RightBrace, scanner.Identifier) {
 		// Name
+		docs := p.nextDocs
 		name := p.consume(scanner.Identifier, "Expected enum case name.")
 
 		if name.IsError() {
@@ -266,7 +288,8 @@ func (p *parser) enum() ast.Decl {
 		validValue := true
 
 		if p.match(scanner.Equal) {
-			literal := p.consume(scanner.Number, "Enum case values can only be integers.")
+			negative := p.match(scanner.Minus)
+			literal := p.consumeInteger("Enum case values can only be integers.")
 
 			if literal.IsError() {
 				if !p.syncBeforeFieldOrDecl() {
@@ -275,14 +298,18 @@ func (p *parser) enum() ast.Decl {
 				continue
 			}
 
-			number, err := strconv.Atoi(literal.Lexeme)
+			number, ok := scanner.ParseInteger(literal)
 
-			if err != nil {
+			if !ok {
 				p.error(literal, "Invalid integer.")
 				validValue = false
 			}
 
-			value = number
+			if negative {
+				number = -number
+			}
+
+			value = int(number)
 			inferValue = false
 		}
 
@@ -291,6 +318,7 @@ func (p *parser) enum() ast.Decl {
 		// Add
 		if validValue {
 			cases = append(cases, ast.EnumCase{
+				Docs:       docs,
 				Name:       name,
 				Value:      value,
 				InferValue: inferValue,
@@ -317,10 +345,13 @@ func (p *parser) enum() ast.Decl {
 
 	// Return
 	decl := &ast.Enum{
-		Name:      name,
-		Type:      type_,
-		InferType: type_ == nil,
-		Cases:     cases,
+		Attributes: attributes,
+		Docs:       docs,
+		Pub:        pub,
+		Name:       name,
+		Type:       type_,
+		InferType:  type_ == nil,
+		Cases:      cases,
 	}
 
 	decl.SetRangeToken(start, p.current)
@@ -329,7 +360,7 @@ func (p *parser) enum() ast.Decl {
 	return decl
 }
 
-func (p *parser) function(start scanner.Token, attributes []any, flags ast.FuncFlags) ast.Decl {
+func (p *parser) function(start scanner.Token, docs string, attributes []ast.Attribute, pub bool, flags ast.FuncFlags) ast.Decl {
 	// Name
 	name := p.consume(scanner.Identifier, "Expected function name.")
 
@@ -347,6 +378,8 @@ func (p *parser) function(start scanner.Token, attributes []any, flags ast.FuncF
 	params := make([]ast.Param, 0, 4)
 
 	for p.canLoop(scanner.RightParen, scanner.Dot) {
+		attributes := p.parseAttributes()
+
 		name := p.consume(scanner.Identifier, "Expected parameter name.")
 		if name.IsError() {
 			p.syncToDecl()
@@ -362,8 +395,9 @@ func (p *parser) function(start scanner.Token, attributes []any, flags ast.FuncF
 		p.match(scanner.Comma)
 
 		params = append(params, ast.Param{
-			Name: name,
-			Type: type_,
+			Attributes: attributes,
+			Name:       name,
+			Type:       type_,
 		})
 	}
 
@@ -394,6 +428,8 @@ func (p *parser) function(start scanner.Token, attributes []any, flags ast.FuncF
 	// Body
 	decl := &ast.Func{
 		Attributes: attributes,
+		Docs:       docs,
+		Pub:        pub,
 		Flags:      flags,
 		Name:       name,
 		Params:     params,
@@ -433,12 +469,79 @@ func (p *parser) function(start scanner.Token, attributes []any, flags ast.FuncF
 	return decl
 }
 
+func (p *parser) staticAssertDecl(attributes []ast.Attribute) ast.Decl {
+	token, condition, message, ok := p.staticAssert()
+	if !ok {
+		p.syncToDecl()
+		return nil
+	}
+
+	decl := &ast.StaticAssert{
+		Attributes: attributes,
+		Token_:     token,
+		Condition:  condition,
+		Message:    message,
+	}
+
+	decl.SetRangeToken(token, p.current)
+	decl.SetChildrenParent()
+
+	return decl
+}
+
+func (p *parser) staticAssert() (scanner.Token, ast.Expr, scanner.Token, bool) {
+	token := p.advance()
+
+	// Left paren
+	if token := p.consume(scanner.LeftParen, "Expected '(' after 'static_assert'."); token.IsError() {
+		return token, nil, token, false
+	}
+
+	// Condition
+	condition := p.expression()
+	if condition == nil {
+		return token, nil, token, false
+	}
+
+	// Message
+	if token := p.consume(scanner.Comma, "Expected ',' after condition."); token.IsError() {
+		return token, nil, token, false
+	}
+
+	message := p.consume(scanner.String, "Expected assertion message.")
+	if message.IsError() {
+		return token, nil, message, false
+	}
+
+	// Right paren
+	if token := p.consume(scanner.RightParen, "Expected ')' after assertion message."); token.IsError() {
+		return token, nil, token, false
+	}
+
+	// Semicolon
+	_ = p.consume(scanner.Semicolon, "Expected ';'.")
+
+	return token, condition, message, true
+}
+
 // Attributes
 
-func (p *parser) parseAttributes() []any {
-	var attributes []any
+// parseDocsAndAttributes returns the doc comment written either before or after the attributes.
+func (p *parser) parseDocsAndAttributes() (string, []ast.Attribute) {
+	docs := p.nextDocs
+	attributes := p.parseAttributes()
+
+	if docs == "" {
+		docs = p.nextDocs
+	}
+
+	return docs, attributes
+}
+
+func (p *parser) parseAttributes() []ast.Attribute {
+	var attributes []ast.Attribute
 
-	if p.match(scanner.Hashtag) {
+	for p.match(scanner.Hashtag) {
 		// [
 		if token := p.consume(scanner.LeftBracket, "Expected '[' before attributes."); token.IsError() {
 			p.syncToDecl()
@@ -446,15 +549,19 @@ func (p *parser) parseAttributes() []any {
 		}
 
 		// Attributes
+		first := true
+
 		for {
 			// Comma
-			if len(attributes) > 0 {
+			if !first {
 				if token := p.consume(scanner.Comma, "Expected ',' between attributes."); token.IsError() {
 					p.syncToDecl()
 					return attributes
 				}
 			}
 
+			first = false
+
 			// Name
 			name := p.consume(scanner.Identifier, "Expected attribute name.")
 			if name.IsError() {
@@ -463,35 +570,26 @@ func (p *parser) parseAttributes() []any {
 			}
 
 			// Args
-			var args []string
-			argsError := false
+			var args []scanner.Token
 
 			if p.match(scanner.LeftParen) {
-				for {
+				for p.canLoop(scanner.RightParen) {
 					// Comma
 					if len(args) > 0 {
-						argsError = true
-						p.syncTo(scanner.RightParen)
-
-						break
+						if token := p.consume(scanner.Comma, "Expected ',' between attribute arguments."); token.IsError() {
+							p.syncToDecl()
+							return attributes
+						}
 					}
 
 					// Value
-					value := p.consume(scanner.String, "Expected attribute argument.")
-
+					value := p.parseAttributeArg()
 					if value.IsError() {
-						argsError = true
-						p.syncTo(scanner.RightParen)
-
-						break
+						p.syncToDecl()
+						return attributes
 					}
 
-					args = append(args, value.Lexeme[1:len(value.Lexeme)-1])
-
-					// Loop
-					if !p.canLoopAdvanced(scanner.RightParen, scanner.Comma) {
-						break
-					}
+					args = append(args, value)
 				}
 
 				// )
@@ -502,16 +600,19 @@ func (p *parser) parseAttributes() []any {
 			}
 
 			// Attribute
-			if !argsError {
-				attribute := p.parseAttribute(name, args)
+			attribute := ast.Attribute{
+				Name: name,
+				Args: args,
+			}
 
-				if attribute == nil {
-					p.error(name, "Unknown attribute with name '%s'.", name)
-				} else {
-					attributes = append(attributes, attribute)
-				}
+			// Built-in attributes are resolved right away since the parser depends on some of them, the rest are
+			// resolved by the checker using the project's attribute schemas
+			if schema := types.GetBuiltinAttribute(name.Lexeme); schema != nil {
+				attribute.Resolve(schema, p.error)
 			}
 
+			attributes = append(attributes, attribute)
+
 			// Loop
 			if !p.canLoopAdvanced(scanner.RightBracket, scanner.Comma) {
 				break
@@ -528,39 +629,25 @@ func (p *parser) parseAttributes() []any {
 	return attributes
 }
 
-func (p *parser) parseAttribute(token scanner.Token, args []string) any {
-	switch token.Lexeme {
-	case "Extern":
-		name := ""
-
-		if len(args) == 1 {
-			name = args[0]
-		} else if len(args) > 1 {
-			p.error(token, "Extern attribute only has 1 optional parameter.")
-		}
-
-		return types.ExternAttribute{Name: name}
-
-	case "Intrinsic":
-		name := ""
+func (p *parser) parseAttributeArg() scanner.Token {
+	// Negative integer
+	if p.match(scanner.Minus) {
+		value := p.consume(scanner.Number, "Expected number after '-'.")
 
-		if len(args) == 1 {
-			name = args[0]
-		} else if len(args) > 1 {
-			p.error(token, "Intrinsic attribute only has 1 optional parameter.")
+		if !value.IsError() {
+			value.Lexeme = "-" + value.Lexeme
 		}
 
-		return types.IntrinsicAttribute{Name: name}
-
-	case "Inline":
-		if len(args) != 0 {
-			p.error(token, "Inline attribute doesn't have any parameters.")
-		}
+		return value
+	}
 
-		return types.InlineAttribute{}
+	switch p.next.Kind {
+	case scanner.String, scanner.Number, scanner.Hex, scanner.Binary, scanner.Octal, scanner.True, scanner.False, scanner.Identifier:
+		return p.advance()
 
 	default:
-		return nil
+		p.error(p.next, "Expected attribute argument.")
+		return scanner.Token{Kind: scanner.Error}
 	}
 }
 
@@ -13,13 +13,13 @@ func (p *parser) expression() ast.Expr {
 
 func (p *parser) assignment() ast.Expr {
 	// Cascade
-	expr := p.logicalOr()
+	expr := p.ternary()
 	if expr == nil {
 		return nil
 	}
 
-	// = += -= *= /= %= |= ^= &= <<= >>=
-	if p.match(scanner.Equal, scanner.PlusEqual, scanner.MinusEqual, scanner.StarEqual, scanner.SlashEqual, scanner.PercentageEqual, scanner.PipeEqual, scanner.XorEqual, scanner.AmpersandEqual, scanner.LessLessEqual, scanner.GreaterGreaterEqual) {
+	// = += -= *= /= %= |= ^= &= <<= >>= +%= -%= *%=
+	if p.match(scanner.Equal, scanner.PlusEqual, scanner.MinusEqual, scanner.StarEqual, scanner.SlashEqual, scanner.PercentageEqual, scanner.PipeEqual, scanner.XorEqual, scanner.AmpersandEqual, scanner.LessLessEqual, scanner.GreaterGreaterEqual, scanner.PlusPercentageEqual, scanner.MinusPercentageEqual, scanner.StarPercentageEqual) {
 		op := p.current
 
 		// Cascade
@@ -45,6 +45,52 @@ func (p *parser) assignment() ast.Expr {
 	return expr
 }
 
+func (p *parser) ternary() ast.Expr {
+	// Cascade
+	expr := p.logicalOr()
+	if expr == nil {
+		return nil
+	}
+
+	// ? :
+	if p.match(scanner.QuestionMark) {
+		token := p.current
+
+		// Then
+		then := p.ternary()
+		if then == nil {
+			return nil
+		}
+
+		// Colon
+		if token := p.consume(scanner.Colon, "Expected ':' after the true value."); token.IsError() {
+			return nil
+		}
+
+		// Else
+		else_ := p.ternary()
+		if else_ == nil {
+			return nil
+		}
+
+		// Return
+		conditional := &ast.IfExpr{
+			Token_:    token,
+			Condition: expr,
+			Then:      then,
+			Else:      else_,
+		}
+
+		conditional.SetRangeNode(expr, else_)
+		conditional.SetChildrenParent()
+
+		return conditional
+	}
+
+	// Return
+	return expr
+}
+
 func (p *parser) logicalOr() ast.Expr {
 	// Cascade
 	expr := p.logicalAnd()
@@ -324,8 +370,8 @@ func (p *parser) term() ast.Expr {
 		return nil
 	}
 
-	// + -
-	for p.match(scanner.Plus, scanner.Minus) {
+	// + - +% -%
+	for p.match(scanner.Plus, scanner.Minus, scanner.PlusPercentage, scanner.MinusPercentage) {
 		op := p.current
 
 		// Cascade
@@ -358,8 +404,8 @@ func (p *parser) factor() ast.Expr {
 		return nil
 	}
 
-	// * / %
-	for p.match(scanner.Star, scanner.Slash, scanner.Percentage) {
+	// * / % *%
+	for p.match(scanner.Star, scanner.Slash, scanner.Percentage, scanner.StarPercentage) {
 		op := p.current
 
 		// Cascade
@@ -581,7 +627,7 @@ func (p *parser) finishCast(value ast.Expr) ast.Expr {
 
 func (p *parser) primary() ast.Expr {
 	// nil true false 0.0 'c' "str"
-	if p.match(scanner.Nil, scanner.True, scanner.False, scanner.Number, scanner.Hex, scanner.Binary, scanner.Character, scanner.String) {
+	if p.match(scanner.Nil, scanner.True, scanner.False, scanner.Number, scanner.Hex, scanner.Binary, scanner.Octal, scanner.Character, scanner.String) {
 		expr := &ast.Literal{
 			Value: p.current,
 		}
@@ -594,7 +640,10 @@ func (p *parser) primary() ast.Expr {
 
 	// abc
 	if p.match(scanner.Identifier) {
-		token := p.current
+		token := p.qualified(p.current)
+		if token.IsError() {
+			return nil
+		}
 
 		// Initializer
 		if p.match(scanner.LeftBrace) {
@@ -606,6 +655,11 @@ func (p *parser) primary() ast.Expr {
 			return p.typeCall(token)
 		}
 
+		// Assert
+		if token.Lexeme == "assert" && p.match(scanner.LeftParen) {
+			return p.assert(token)
+		}
+
 		// New
 		if token.Lexeme == "new" && p.check(scanner.Identifier) {
 			type_ := p.parseType()
@@ -644,6 +698,16 @@ func (p *parser) primary() ast.Expr {
 		return p.arrayInitializer()
 	}
 
+	// if
+	if p.match(scanner.If) {
+		return p.ifExpr()
+	}
+
+	// {
+	if p.match(scanner.LeftBrace) {
+		return p.blockExpr()
+	}
+
 	// (
 	if p.match(scanner.LeftParen) {
 		token := p.current
@@ -676,6 +740,112 @@ func (p *parser) primary() ast.Expr {
 	return nil
 }
 
+func (p *parser) ifExpr() ast.Expr {
+	token := p.current
+
+	// Left paren
+	if token := p.consume(scanner.LeftParen, "Expected '(' before condition."); token.IsError() {
+		return nil
+	}
+
+	// Condition
+	condition := p.expression()
+	if condition == nil {
+		return nil
+	}
+
+	// Right paren
+	if token := p.consume(scanner.RightParen, "Expected ')' after condition."); token.IsError() {
+		return nil
+	}
+
+	// Then
+	then := p.expression()
+	if then == nil {
+		return nil
+	}
+
+	// Else
+	if token := p.consume(scanner.Else, "Expected 'else' in an if expression."); token.IsError() {
+		return nil
+	}
+
+	else_ := p.expression()
+	if else_ == nil {
+		return nil
+	}
+
+	// Return
+	expr := &ast.IfExpr{
+		Token_:    token,
+		Condition: condition,
+		Then:      then,
+		Else:      else_,
+	}
+
+	expr.SetRangeToken(token, p.current)
+	expr.SetChildrenParent()
+
+	return expr
+}
+
+func (p *parser) blockExpr() ast.Expr {
+	token := p.current
+
+	// Statements and the value, which is the last expression not followed by a semicolon
+	stmts := make([]ast.Stmt, 0, 4)
+	var value ast.Expr
+
+	for p.canLoop(scanner.RightBrace) {
+		var stmt ast.Stmt
+
+		if p.startsStatement() {
+			stmt = p.statement()
+		} else {
+			start := p.next
+
+			expr := p.expression()
+			if expr == nil {
+				if !p.syncToStmt() {
+					break
+				}
+				continue
+			}
+
+			if p.check(scanner.RightBrace) {
+				value = expr
+				break
+			}
+
+			stmt = p.finishExpressionStmt(start, expr)
+		}
+
+		if stmt == nil {
+			if !p.syncToStmt() {
+				break
+			}
+			continue
+		}
+
+		stmts = append(stmts, stmt)
+	}
+
+	// Right brace
+	_ = p.consume(scanner.RightBrace, "Expected '}'.")
+
+	// Return
+	expr := &ast.BlockExpr{
+		Token_: token,
+		Stmts:  stmts,
+		Value:  value,
+	}
+
+	expr.SetRangeToken(token, p.current)
+	expr.SetChildrenParent()
+
+	return expr
+}
+
 func (p *parser) structInitializer(new bool, token scanner.Token, target types.Type) ast.Expr {
 	// Fields
 	fields := make([]ast.InitField, 0, 4)
@@ -793,6 +963,41 @@ func (p *parser) newArray(token scanner.Token, type_ types.Type) ast.Expr {
 	return expr
 }
 
+func (p *parser) assert(name scanner.Token) ast.Expr {
+	// Condition
+	condition := p.expression()
+	if condition == nil {
+		return nil
+	}
+
+	// Message
+	var message scanner.Token
+
+	if p.match(scanner.Comma) {
+		message = p.consume(scanner.String, "Expected message string.")
+		if message.IsError() {
+			return nil
+		}
+	}
+
+	// Right paren
+	if token := p.consume(scanner.RightParen, "Expected ')' after assert."); token.IsError() {
+		return nil
+	}
+
+	// Return
+	expr := &ast.Assert{
+		Token_:    name,
+		Condition: condition,
+		Message:   message,
+	}
+
+	expr.SetRangeToken(name, p.current)
+	expr.SetChildrenParent()
+
+	return expr
+}
+
 func (p *parser) typeCall(name scanner.Token) ast.Expr {
 	// Type
 	type_ := p.parseType()
@@ -7,7 +7,7 @@ import (
 	"fireball/core/types"
 	"fireball/core/utils"
 	"fmt"
-	"strconv"
+	"math"
 )
 
 type parser struct {
@@ -17,14 +17,21 @@ type parser struct {
 	current  scanner.Token
 	next     scanner.Token
 
+	// Doc comment of the next token
+	nextDocs string
+
+	// Namespaces of dependencies, identifiers followed by '.name' are merged into a single qualified identifier
+	namespaces utils.Set[string]
+
 	reporter utils.Reporter
 }
 
-func Parse(reporter utils.Reporter, scanner *scanner.Scanner) []ast.Decl {
+func Parse(reporter utils.Reporter, scanner *scanner.Scanner, namespaces utils.Set[string]) []ast.Decl {
 	// Initialise parser
 	p := &parser{
-		scanner:  scanner,
-		reporter: reporter,
+		scanner:    scanner,
+		namespaces: namespaces,
+		reporter:   reporter,
 	}
 
 	p.advance()
@@ -63,19 +70,14 @@ func (p *parser) parseArrayType() types.Type {
 	start := p.current
 
 	// Count
-	token := p.consume(scanner.Number, "Expected array size.")
+	token := p.consumeInteger("Expected array size.")
 	if token.IsError() {
 		return nil
 	}
 
-	count, err_ := strconv.Atoi(token.Lexeme)
+	count, ok := scanner.ParseInteger(token)
 
-	if err_ != nil {
-		p.error(token, "Invalid array size.")
-		return nil
-	}
-
-	if count < 0 {
+	if !ok || count < 0 || count > math.MaxUint32 {
 		p.error(token, "Invalid array size.")
 		return nil
 	}
@@ -97,6 +99,7 @@ func (p *parser) parseArrayType() types.Type {
 
 func (p *parser) parsePointerType() types.Type {
 	start := p.current
+	const_ := p.match(scanner.Const)
 
 	// Pointee
 	pointee := p.parseType()
@@ -105,6 +108,10 @@ func (p *parser) parsePointerType() types.Type {
 	}
 
 	// return
+	if const_ {
+		return types.ConstPointer(pointee, core.TokensToRange(start, p.current))
+	}
+
 	return types.Pointer(pointee, core.TokensToRange(start, p.current))
 }
 
@@ -121,6 +128,14 @@ func (p *parser) parseFunctionType() types.Type {
 			return nil
 		}
 
+		// Bound method, the instance is remembered by the value
+		if len(params) == 0 && flags&ast.Bound == 0 && name.Lexeme == "this" && (p.check(scanner.Comma) || p.check(scanner.RightParen)) {
+			flags |= ast.Bound
+
+			p.match(scanner.Comma)
+			continue
+		}
+
 		type_ := p.parseType()
 		if type_ == nil {
 			return nil
@@ -161,11 +176,34 @@ func (p *parser) parseFunctionType() types.Type {
 
 func (p *parser) parseIdentifierType() types.Type {
 	// Name
-	ident := p.consume(scanner.Identifier, "Expected type name.")
+	ident := p.qualified(p.consume(scanner.Identifier, "Expected type name."))
 	if ident.IsError() {
 		return nil
 	}
 
+	return identifierType(ident)
+}
+
+// qualified merges an identifier naming a namespace with the following '.name' into a single identifier like
+// 'math.Vec2', declarations of dependencies are resolved by their qualified name.
+func (p *parser) qualified(ident scanner.Token) scanner.Token {
+	if ident.IsError() || !p.namespaces.Contains(ident.Lexeme) || !p.check(scanner.Dot) {
+		return ident
+	}
+
+	p.advance()
+
+	name := p.consume(scanner.Identifier, "Expected name after namespace.")
+	if name.IsError() {
+		return name
+	}
+
+	ident.Lexeme += "." + name.Lexeme
+	return ident
+}
+
+// identifierType returns the primitive type with the name of the identifier or an unresolved type.
+func identifierType(ident scanner.Token) types.Type {
 	range_ := core.TokenToRange(ident)
 
 	// Select kind
@@ -220,6 +258,18 @@ func (p *parser) consume(kind scanner.TokenKind, msg string) scanner.Token {
 	return scanner.Token{Kind: scanner.Error}
 }
 
+// consumeInteger consumes a decimal, hexadecimal, binary or octal number.
+func (p *parser) consumeInteger(msg string) scanner.Token {
+	switch p.next.Kind {
+	case scanner.Number, scanner.Hex, scanner.Binary, scanner.Octal:
+		return p.advance()
+
+	default:
+		p.error(p.next, msg)
+		return scanner.Token{Kind: scanner.Error}
+	}
+}
+
 func (p *parser) match(kinds ...scanner.TokenKind) bool {
 	for _, kind := range kinds {
 		if p.check(kind) {
@@ -243,6 +293,7 @@ func (p *parser) advance() scanner.Token {
 		p.previous = p.current
 		p.current = p.next
 		p.next = p.scanner.Next()
+		p.nextDocs = p.scanner.Docs()
 	}
 
 	return p.current
@@ -290,7 +341,7 @@ func (p *parser) isAtEnd() bool {
 // Error handling
 
 func (p *parser) syncToDecl() {
-	p.syncTo(scanner.Struct, scanner.Enum, scanner.Static, scanner.Func)
+	p.syncTo(scanner.Struct, scanner.Enum, scanner.Pub, scanner.Static, scanner.Func)
 }
 
 func (p *parser) syncToStmt() bool {
@@ -300,7 +351,7 @@ func (p *parser) syncToStmt() bool {
 			p.advance()
 			return true
 
-		case scanner.Struct, scanner.Enum, scanner.Static, scanner.Func, scanner.RightBrace:
+		case scanner.Struct, scanner.Enum, scanner.Pub, scanner.Static, scanner.Func, scanner.RightBrace:
 			return false
 
 		default:
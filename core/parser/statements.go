@@ -1,16 +1,28 @@
 package parser
 
 import (
+	"fireball/core"
 	"fireball/core/ast"
 	"fireball/core/scanner"
 	"fireball/core/types"
 )
 
 func (p *parser) statement() ast.Stmt {
+	attributes := p.parseAttributes()
+	stmt := p.bareStatement()
+
+	if stmt != nil && len(attributes) > 0 {
+		stmt.(ast.Attributed).SetAttributes(attributes)
+	}
+
+	return stmt
+}
+
+func (p *parser) bareStatement() ast.Stmt {
 	if p.match(scanner.LeftBrace) {
 		return p.block()
 	}
-	if p.match(scanner.Var) {
+	if p.match(scanner.Var, scanner.Let) {
 		return p.variable()
 	}
 	if p.match(scanner.If) {
@@ -28,6 +40,9 @@ func (p *parser) statement() ast.Stmt {
 	if p.match(scanner.Continue) {
 		return p.continue_()
 	}
+	if p.check(scanner.Identifier) && p.next.Lexeme == "static_assert" {
+		return p.staticAssertStmt()
+	}
 
 	return p.expressionStmt()
 }
@@ -66,6 +81,16 @@ func (p *parser) block() ast.Stmt {
 	return stmt
 }
 
+// startsStatement returns true if the next token starts a statement inside a block expression, 'if' and '{' are parsed
+// as statements there so an if or block expression used as the value needs to be wrapped in parentheses.
+func (p *parser) startsStatement() bool {
+	if p.check(scanner.Hashtag) || p.check(scanner.LeftBrace) || p.check(scanner.Var) || p.check(scanner.Let) || p.check(scanner.If) || p.check(scanner.For) || p.check(scanner.Return) || p.check(scanner.Break) || p.check(scanner.Continue) {
+		return true
+	}
+
+	return p.check(scanner.Identifier) && p.next.Lexeme == "static_assert"
+}
+
 func (p *parser) expressionStmt() ast.Stmt {
 	token := p.next
 
@@ -75,11 +100,16 @@ func (p *parser) expressionStmt() ast.Stmt {
 		return nil
 	}
 
+	return p.finishExpressionStmt(token, expr)
+}
+
+func (p *parser) finishExpressionStmt(token scanner.Token, expr ast.Expr) ast.Stmt {
 	_, isAssignment := expr.(*ast.Assignment)
 	_, isCall := expr.(*ast.Call)
+	_, isAssert := expr.(*ast.Assert)
 	unary, isUnary := expr.(*ast.Unary)
 
-	if !isAssignment && !isCall && !isUnary {
+	if !isAssignment && !isCall && !isAssert && !isUnary {
 		p.error(token, "Invalid statement.")
 		return nil
 	}
@@ -107,16 +137,28 @@ func (p *parser) expressionStmt() ast.Stmt {
 func (p *parser) variable() ast.Stmt {
 	start := p.current
 
+	// Destructure
+	if p.match(scanner.LeftBracket) {
+		return p.destructure(start, nil, true)
+	}
+	if p.match(scanner.LeftBrace) {
+		return p.destructure(start, nil, false)
+	}
+
 	// Name
 	name := p.consume(scanner.Identifier, "expected variable name")
 	if name.IsError() {
 		return nil
 	}
 
+	if p.match(scanner.LeftBrace) {
+		return p.destructure(start, types.Unresolved(name, core.TokenToRange(name)), false)
+	}
+
 	// Type
 	var type_ types.Type
 
-	if !p.check(scanner.Equal) {
+	if !p.check(scanner.Equal) && !p.check(scanner.Semicolon) {
 		type__ := p.parseType()
 		if type__ == nil {
 			return nil
@@ -128,6 +170,11 @@ func (p *parser) variable() ast.Stmt {
 	// Initializer
 	var initializer ast.Expr
 
+	if start.Kind == scanner.Let && p.check(scanner.Semicolon) {
+		p.error(name, "Immutable variables need an initializer.")
+		return nil
+	}
+
 	if !p.check(scanner.Semicolon) {
 		if token := p.consume(scanner.Equal, "Expected '='."); token.IsError() {
 			return nil
@@ -148,6 +195,7 @@ func (p *parser) variable() ast.Stmt {
 		Name:        name,
 		Initializer: initializer,
 		InferType:   type_ == nil,
+		Immutable:   start.Kind == scanner.Let,
 	}
 
 	stmt.SetRangeToken(start, p.current)
@@ -156,6 +204,107 @@ func (p *parser) variable() ast.Stmt {
 	return stmt
 }
 
+func (p *parser) destructure(start scanner.Token, type_ types.Type, array bool) ast.Stmt {
+	end := scanner.RightBrace
+	if array {
+		end = scanner.RightBracket
+	}
+
+	// Bindings
+	bindings := make([]ast.Stmt, 0, 4)
+
+	for p.canLoop(end) {
+		// Comma
+		if len(bindings) > 0 {
+			if token := p.consume(scanner.Comma, "expected ',' between bindings"); token.IsError() {
+				return nil
+			}
+		}
+
+		// Name
+		name := p.consume(scanner.Identifier, "expected binding name")
+		if name.IsError() {
+			return nil
+		}
+
+		if name.Lexeme == "_" {
+			if !array {
+				p.error(name, "struct patterns cannot skip fields")
+				return nil
+			}
+
+			bindings = append(bindings, nil)
+			continue
+		}
+
+		// Add
+		binding := &ast.Variable{
+			Name:      name,
+			InferType: true,
+			Immutable: start.Kind == scanner.Let,
+		}
+
+		binding.SetRangeToken(name, name)
+		bindings = append(bindings, binding)
+	}
+
+	// End
+	if array {
+		if token := p.consume(scanner.RightBracket, "expected ']' after bindings"); token.IsError() {
+			return nil
+		}
+	} else {
+		if token := p.consume(scanner.RightBrace, "expected '}' after bindings"); token.IsError() {
+			return nil
+		}
+	}
+
+	// Initializer
+	if token := p.consume(scanner.Equal, "expected '='"); token.IsError() {
+		return nil
+	}
+
+	initializer := p.expression()
+	if initializer == nil {
+		return nil
+	}
+
+	// Semicolon
+	_ = p.consume(scanner.Semicolon, "expected ';'")
+
+	// Return
+	stmt := &ast.Destructure{
+		Token_:      start,
+		Type:        type_,
+		Array:       array,
+		Bindings:    bindings,
+		Initializer: initializer,
+	}
+
+	stmt.SetRangeToken(start, p.current)
+	stmt.SetChildrenParent()
+
+	return stmt
+}
+
+func (p *parser) staticAssertStmt() ast.Stmt {
+	token, condition, message, ok := p.staticAssert()
+	if !ok {
+		return nil
+	}
+
+	stmt := &ast.StaticAssertStmt{
+		Token_:    token,
+		Condition: condition,
+		Message:   message,
+	}
+
+	stmt.SetRangeToken(token, p.current)
+	stmt.SetChildrenParent()
+
+	return stmt
+}
+
 func (p *parser) if_() ast.Stmt {
 	token := p.current
 
@@ -218,7 +367,7 @@ func (p *parser) for_() ast.Stmt {
 	// Initializer
 	var initializer ast.Stmt
 
-	if p.match(scanner.Var) {
+	if p.match(scanner.Var, scanner.Let) {
 		initializer = p.variable()
 		if initializer == nil {
 			return nil
@@ -0,0 +1,203 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"go/constant"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseNumber returns the arbitrary precision value of a Number, Hex, Binary or Octal token together with its type
+// suffix, the value is unknown if the number is malformed. The legacy 'f' suffix is returned as 'f32'.
+func ParseNumber(number Token) (constant.Value, string) {
+	raw := strings.ToLower(number.Lexeme)
+
+	negative := strings.HasPrefix(raw, "-")
+	raw = strings.TrimPrefix(raw, "-")
+
+	// Split suffix
+	start := 0
+
+	switch number.Kind {
+	case Hex, Binary, Octal:
+		start = 2
+	case Number:
+	default:
+		return constant.MakeUnknown(), ""
+	}
+
+	end := len(raw)
+
+	for i := start; i < len(raw); i++ {
+		c := raw[i]
+
+		// Exponent
+		if number.Kind == Number && c == 'e' && i+1 < len(raw) && (isDigit(raw[i+1]) || raw[i+1] == '+' || raw[i+1] == '-') {
+			i++
+			continue
+		}
+
+		if isAlpha(c) && c != '_' && (number.Kind != Hex || !isHex(c)) {
+			end = i
+			break
+		}
+	}
+
+	raw, suffix := raw[:end], raw[end:]
+
+	if suffix == "f" {
+		suffix = "f32"
+	}
+
+	// Parse value
+	kind := token.INT
+
+	if number.Kind == Number && strings.ContainsAny(raw, ".e") {
+		kind = token.FLOAT
+	}
+
+	value := constant.MakeFromLiteral(raw, kind, 0)
+
+	if negative {
+		value = constant.UnaryOp(token.SUB, value, 0)
+	}
+
+	return value, suffix
+}
+
+// ParseInteger returns the value of a Number, Hex, Binary or Octal token, it fails for malformed numbers, floats,
+// numbers with a type suffix and numbers not fitting into an int64.
+func ParseInteger(number Token) (int64, bool) {
+	value, suffix := ParseNumber(number)
+	if value.Kind() != constant.Int || suffix != "" {
+		return 0, false
+	}
+
+	return constant.Int64Val(value)
+}
+
+// ParseString returns the contents of a String token with escape sequences decoded. Raw strings are returned as
+// written and multi-line strings have their first line break and common indentation removed.
+func ParseString(str Token) (string, error) {
+	raw := str.Lexeme
+
+	switch {
+	case strings.HasPrefix(raw, "`"):
+		return strings.ReplaceAll(raw[1:len(raw)-1], "\r\n", "\n"), nil
+
+	case strings.HasPrefix(raw, `"""`):
+		return unescape(dedent(raw[3 : len(raw)-3]))
+
+	default:
+		return unescape(raw[1 : len(raw)-1])
+	}
+}
+
+// ParseCharacter returns the value of a Character token.
+func ParseCharacter(char Token) (uint8, error) {
+	value, err := unescape(char.Lexeme[1 : len(char.Lexeme)-1])
+	if err != nil {
+		return 0, err
+	}
+
+	if len(value) != 1 {
+		return 0, errors.New("character needs to be a single byte")
+	}
+
+	return value[0], nil
+}
+
+func unescape(raw string) (string, error) {
+	if !strings.ContainsRune(raw, '\\') {
+		return raw, nil
+	}
+
+	str := strings.Builder{}
+	str.Grow(len(raw))
+
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			str.WriteByte(raw[i])
+			continue
+		}
+
+		i++
+
+		if i >= len(raw) {
+			return "", errors.New("unterminated escape sequence")
+		}
+
+		switch raw[i] {
+		case '0':
+			str.WriteByte(0)
+		case 'n':
+			str.WriteByte('\n')
+		case 'r':
+			str.WriteByte('\r')
+		case 't':
+			str.WriteByte('\t')
+		case '\\', '\'', '"':
+			str.WriteByte(raw[i])
+
+		case 'u':
+			end := strings.IndexByte(raw[i:], '}')
+
+			if !strings.HasPrefix(raw[i:], "u{") || end == -1 {
+				return "", errors.New("expected '\\u{...}' unicode escape")
+			}
+
+			code, err := strconv.ParseUint(raw[i+2:i+end], 16, 32)
+			if err != nil || end-2 > 6 || !utf8.ValidRune(rune(code)) {
+				return "", fmt.Errorf("invalid unicode code point '%s'", raw[i+2:i+end])
+			}
+
+			str.WriteRune(rune(code))
+			i += end
+
+		default:
+			return "", fmt.Errorf("invalid escape sequence '\\%c'", raw[i])
+		}
+	}
+
+	return str.String(), nil
+}
+
+// dedent removes the line break after the opening quotes, the last line if it only contains the indentation of the
+// closing quotes and the common indentation of all non-empty lines.
+func dedent(raw string) string {
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+	raw = strings.TrimPrefix(raw, "\n")
+
+	lines := strings.Split(raw, "\n")
+
+	if last := lines[len(lines)-1]; len(lines) > 1 && strings.TrimLeft(last, " \t") == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indentation := -1
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if indentation == -1 || indent < indentation {
+			indentation = indent
+		}
+	}
+
+	for i, line := range lines {
+		if len(line) >= indentation && indentation > 0 {
+			lines[i] = line[indentation:]
+		} else if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -1,5 +1,7 @@
 package scanner
 
+import "strings"
+
 type Scanner struct {
 	text string
 
@@ -8,6 +10,11 @@ type Scanner struct {
 
 	line   int
 	column int
+
+	startLine   int
+	startColumn int
+
+	docs []string
 }
 
 func NewScanner(text string) *Scanner {
@@ -19,9 +26,17 @@ func NewScanner(text string) *Scanner {
 	}
 }
 
+// Docs returns the '///' doc comment lines directly before the last returned token.
+func (s *Scanner) Docs() string {
+	return strings.Join(s.docs, "\n")
+}
+
 func (s *Scanner) Next() Token {
+	s.docs = s.docs[:0]
 	s.skipWhitespace()
 	s.startI = s.currentI
+	s.startLine = s.line
+	s.startColumn = s.column
 
 	if s.isAtEnd() {
 		return s.make(Eof)
@@ -32,7 +47,7 @@ func (s *Scanner) Next() Token {
 	if isAlpha(c) {
 		return s.identifier()
 	}
-	if isDigit(c) || (c == '-' && isDigit(s.peek())) {
+	if isDigit(c) {
 		return s.number(c)
 	}
 
@@ -58,20 +73,32 @@ func (s *Scanner) Next() Token {
 		return s.make(Colon)
 	case ';':
 		return s.make(Semicolon)
+	case '?':
+		return s.make(QuestionMark)
 
 	case '+':
 		if s.match('+') {
 			return s.make(PlusPlus)
 		}
+		if s.match('%') {
+			return s.matchToken('=', PlusPercentageEqual, PlusPercentage)
+		}
 
 		return s.matchToken('=', PlusEqual, Plus)
 	case '-':
 		if s.match('-') {
 			return s.make(MinusMinus)
 		}
+		if s.match('%') {
+			return s.matchToken('=', MinusPercentageEqual, MinusPercentage)
+		}
 
 		return s.matchToken('=', MinusEqual, Minus)
 	case '*':
+		if s.match('%') {
+			return s.matchToken('=', StarPercentageEqual, StarPercentage)
+		}
+
 		return s.matchToken('=', StarEqual, Star)
 	case '/':
 		return s.matchToken('=', SlashEqual, Slash)
@@ -124,7 +151,16 @@ func (s *Scanner) Next() Token {
 	case '\'':
 		return s.character()
 	case '"':
+		if s.peek() == '"' && s.peekNext() == '"' {
+			s.advance()
+			s.advance()
+
+			return s.multilineString()
+		}
+
 		return s.string()
+	case '`':
+		return s.rawString()
 	}
 
 	return s.error("Unexpected character.")
@@ -145,7 +181,16 @@ func (s *Scanner) identifierKind() TokenKind {
 	case 'b':
 		return s.checkKeyword(1, "reak", Break)
 	case 'c':
-		return s.checkKeyword(1, "ontinue", Continue)
+		if s.currentI-s.startI > 3 {
+			switch s.text[s.startI+3] {
+			case 's':
+				return s.checkKeyword(1, "onst", Const)
+			case 't':
+				return s.checkKeyword(1, "ontinue", Continue)
+			}
+		}
+	case 'l':
+		return s.checkKeyword(1, "et", Let)
 	case 'e':
 		if s.currentI-s.startI > 1 {
 			switch s.text[s.startI+1] {
@@ -177,6 +222,8 @@ func (s *Scanner) identifierKind() TokenKind {
 		}
 	case 'n':
 		return s.checkKeyword(1, "il", Nil)
+	case 'p':
+		return s.checkKeyword(1, "ub", Pub)
 	case 'r':
 		return s.checkKeyword(1, "eturn", Return)
 	case 's':
@@ -215,16 +262,20 @@ func (s *Scanner) checkKeyword(start int, rest string, kind TokenKind) TokenKind
 func (s *Scanner) number(c uint8) Token {
 	next := s.peek()
 
-	// Hex
-	if c == '0' && (next == 'x' || next == 'X') {
-		s.advance()
-		return s.hex()
-	}
+	if c == '0' {
+		switch next {
+		case 'x', 'X':
+			s.advance()
+			return s.digits(isHex, Hex)
 
-	// Binary
-	if c == '0' && (next == 'b' || next == 'B') {
-		s.advance()
-		return s.binary()
+		case 'b', 'B':
+			s.advance()
+			return s.digits(isDigit, Binary)
+
+		case 'o', 'O':
+			s.advance()
+			return s.digits(isDigit, Octal)
+		}
 	}
 
 	// Integers or floats
@@ -232,52 +283,99 @@ func (s *Scanner) number(c uint8) Token {
 }
 
 func (s *Scanner) integerOrFloat() Token {
-	for isDigit(s.peek()) {
-		s.advance()
-	}
+	s.skipDigits(isDigit)
 
+	// Fraction
 	if s.peek() == '.' && isDigit(s.peekNext()) {
 		s.advance()
+		s.skipDigits(isDigit)
+	}
+
+	// Exponent
+	if s.peek() == 'e' || s.peek() == 'E' {
+		next := s.peekNext()
 
-		for isDigit(s.peek()) {
+		if isDigit(next) || ((next == '+' || next == '-') && isDigit(s.peekAt(2))) {
+			s.advance()
 			s.advance()
-		}
-	}
 
-	if s.peek() == 'f' || s.peek() == 'F' {
-		s.advance()
+			s.skipDigits(isDigit)
+		}
 	}
 
+	s.suffix()
 	return s.make(Number)
 }
 
-func (s *Scanner) hex() Token {
-	for isHex(s.peek()) {
-		s.advance()
-	}
+// digits scans the digits of a prefixed number, invalid digits for the base are reported by the checker.
+func (s *Scanner) digits(valid func(c uint8) bool, kind TokenKind) Token {
+	s.skipDigits(valid)
 
-	return s.make(Hex)
+	s.suffix()
+	return s.make(kind)
 }
 
-func (s *Scanner) binary() Token {
-	for isBinary(s.peek()) {
+// skipDigits skips digits and '_' separators, misplaced separators are reported by the checker.
+func (s *Scanner) skipDigits(valid func(c uint8) bool) {
+	for valid(s.peek()) || s.peek() == '_' {
 		s.advance()
 	}
+}
 
-	return s.make(Binary)
+// suffix consumes a type suffix like 'u8' or 'f32' directly following a number, it is validated by the checker.
+func (s *Scanner) suffix() {
+	if isAlpha(s.peek()) {
+		for isAlpha(s.peek()) || isDigit(s.peek()) {
+			s.advance()
+		}
+	}
 }
 
 func (s *Scanner) string() Token {
 	for s.peek() != '"' && !s.isAtEnd() {
-		if s.peek() == '\n' {
-			s.line++
+		// Escape sequences are validated by the checker
+		if s.peek() == '\\' {
+			s.advance()
 		}
 
-		s.advance()
+		s.advanceLine()
+	}
+
+	if s.isAtEnd() {
+		return s.error("Unterminated string.")
+	}
+
+	s.advance()
+	return s.make(String)
+}
+
+func (s *Scanner) multilineString() Token {
+	for !s.isAtEnd() && !strings.HasPrefix(s.text[s.currentI:], `"""`) {
+		if s.peek() == '\\' {
+			s.advance()
+		}
+
+		s.advanceLine()
+	}
+
+	if s.isAtEnd() {
+		return s.error("Unterminated multi-line string.")
+	}
+
+	s.advance()
+	s.advance()
+	s.advance()
+
+	return s.make(String)
+}
+
+func (s *Scanner) rawString() Token {
+	for s.peek() != '`' && !s.isAtEnd() {
+		s.advanceLine()
 	}
 
 	if s.isAtEnd() {
-		return s.error("Unterminated string")
+		return s.error("Unterminated raw string.")
 	}
 
 	s.advance()
@@ -292,7 +390,23 @@ func (s *Scanner) character() Token {
 	if s.advance() == '\\' && !s.isAtEnd() {
 		c := s.advance()
 
-		if c != '\'' && c != '0' && c != 'n' && c != 'r' && c != 't' {
+		switch c {
+		case '\'', '"', '\\', '0', 'n', 'r', 't':
+
+		case 'u':
+			if s.peek() != '{' {
+				return s.error("Expected '{' after '\\u'.")
+			}
+
+			for !s.isAtEnd() && s.peek() != '}' && s.peek() != '\'' {
+				s.advance()
+			}
+
+			if !s.match('}') {
+				return s.error("Expected '}' after unicode escape.")
+			}
+
+		default:
 			return s.error("Unexpected character.")
 		}
 	}
@@ -345,9 +459,13 @@ func (s *Scanner) skipWhitespace() {
 
 		case '/':
 			if s.peekNext() == '/' {
+				start := s.currentI
+
 				for !s.isAtEnd() && s.peek() != '\n' {
 					s.advance()
 				}
+
+				s.comment(s.text[start:s.currentI])
 			} else if s.peekNext() == '*' {
 				s.advance()
 				s.advance()
@@ -368,6 +486,9 @@ func (s *Scanner) skipWhitespace() {
 						s.advance()
 					}
 				}
+
+				// Block comments separate doc comments from the documented token too
+				s.docs = s.docs[:0]
 			} else {
 				return
 			}
@@ -378,6 +499,17 @@ func (s *Scanner) skipWhitespace() {
 	}
 }
 
+func (s *Scanner) comment(text string) {
+	text = strings.TrimSuffix(text, "\r")
+
+	if strings.HasPrefix(text, "///") && !strings.HasPrefix(text, "////") {
+		s.docs = append(s.docs, strings.TrimPrefix(text[3:], " "))
+	} else {
+		// Doc comments need to be directly above the documented token
+		s.docs = s.docs[:0]
+	}
+}
+
 func (s *Scanner) peek() uint8 {
 	if s.isAtEnd() {
 		return '\000'
@@ -387,11 +519,15 @@ func (s *Scanner) peek() uint8 {
 }
 
 func (s *Scanner) peekNext() uint8 {
-	if s.isAtEnd() {
+	return s.peekAt(1)
+}
+
+func (s *Scanner) peekAt(offset int) uint8 {
+	if s.currentI+offset >= len(s.text) {
 		return '\000'
 	}
 
-	return s.text[s.currentI+1]
+	return s.text[s.currentI+offset]
 }
 
 func (s *Scanner) advance() uint8 {
@@ -401,6 +537,18 @@ func (s *Scanner) advance() uint8 {
 	return s.text[s.currentI-1]
 }
 
+// advanceLine advances by one character which can be a new line.
+func (s *Scanner) advanceLine() {
+	if s.isAtEnd() {
+		return
+	}
+
+	if s.advance() == '\n' {
+		s.line++
+		s.column = 0
+	}
+}
+
 func (s *Scanner) isAtEnd() bool {
 	return s.currentI >= len(s.text)
 }
@@ -411,8 +559,8 @@ func (s *Scanner) make(kind TokenKind) Token {
 	return Token{
 		Kind:   kind,
 		Lexeme: lexeme,
-		line:   s.line,
-		column: s.column - len(lexeme),
+		line:   s.startLine,
+		column: s.startColumn,
 	}
 }
 
@@ -436,7 +584,3 @@ func isDigit(c uint8) bool {
 func isHex(c uint8) bool {
 	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
 }
-
-func isBinary(c uint8) bool {
-	return c == '0' || c == '1'
-}
@@ -14,6 +14,7 @@ const (
 	Comma
 	Colon
 	Semicolon
+	QuestionMark
 
 	Plus
 	Minus
@@ -27,6 +28,14 @@ const (
 	SlashEqual
 	PercentageEqual
 
+	PlusPercentage
+	MinusPercentage
+	StarPercentage
+
+	PlusPercentageEqual
+	MinusPercentageEqual
+	StarPercentageEqual
+
 	PlusPlus
 	MinusMinus
 
@@ -58,6 +67,9 @@ const (
 	And
 	Or
 	Var
+	Let
+	Const
+	Pub
 	If
 	Else
 	While
@@ -75,6 +87,7 @@ const (
 	Number
 	Hex
 	Binary
+	Octal
 	Character
 	String
 	Identifier
@@ -131,6 +144,16 @@ func IsArithmetic(kind TokenKind) bool {
 	}
 }
 
+func IsWrapping(kind TokenKind) bool {
+	switch kind {
+	case PlusPercentage, PlusPercentageEqual, MinusPercentage, MinusPercentageEqual, StarPercentage, StarPercentageEqual:
+		return true
+
+	default:
+		return false
+	}
+}
+
 func IsBitwise(kind TokenKind) bool {
 	switch kind {
 	case Pipe, PipeEqual, Xor, XorEqual, Ampersand, AmpersandEqual, LessLess, LessLessEqual, GreaterGreater, GreaterGreaterEqual:
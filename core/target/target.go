@@ -0,0 +1,202 @@
+package target
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Target is the machine code is generated for, it is described by an LLVM target triple in the form of
+// 'arch-vendor-os-environment'.
+type Target struct {
+	Arch        string
+	Vendor      string
+	OS          string
+	Environment string
+}
+
+// Parse parses a target triple, the vendor and environment can be omitted. Common aliases like 'arm64' or 'i386' are
+// replaced with the names used by LLVM.
+func Parse(triple string) (Target, error) {
+	parts := strings.Split(strings.ToLower(triple), "-")
+
+	if len(parts) < 2 || len(parts) > 4 {
+		return Target{}, fmt.Errorf("invalid target triple '%s', expected 'arch-vendor-os-environment'", triple)
+	}
+
+	t := Target{Arch: parts[0]}
+
+	switch t.Arch {
+	case "x86_64", "amd64":
+		t.Arch = "x86_64"
+	case "aarch64", "arm64":
+		t.Arch = "aarch64"
+	case "i386", "i486", "i586", "i686", "x86":
+		t.Arch = "i686"
+	case "arm", "armv7", "armv7a", "armv7l":
+		t.Arch = "armv7"
+	default:
+		return Target{}, fmt.Errorf("unsupported target architecture '%s'", parts[0])
+	}
+
+	switch len(parts) {
+	case 2:
+		t.OS = parts[1]
+	case 3:
+		if isOS(parts[1]) {
+			t.OS = parts[1]
+			t.Environment = parts[2]
+		} else {
+			t.Vendor = parts[1]
+			t.OS = parts[2]
+		}
+	case 4:
+		t.Vendor = parts[1]
+		t.OS = parts[2]
+		t.Environment = parts[3]
+	}
+
+	switch t.OS {
+	case "macos", "macosx":
+		t.OS = "darwin"
+	}
+
+	if !isOS(t.OS) {
+		return Target{}, fmt.Errorf("unsupported target operating system '%s'", t.OS)
+	}
+
+	// Defaults
+	if t.Vendor == "" {
+		if t.OS == "darwin" {
+			t.Vendor = "apple"
+		} else {
+			t.Vendor = "unknown"
+		}
+	}
+
+	if t.Environment == "" {
+		switch t.OS {
+		case "linux":
+			if t.Arch == "armv7" {
+				t.Environment = "gnueabihf"
+			} else {
+				t.Environment = "gnu"
+			}
+		case "windows":
+			t.Environment = "msvc"
+		}
+	}
+
+	return t, nil
+}
+
+func isOS(os string) bool {
+	switch os {
+	case "linux", "darwin", "macos", "macosx", "windows":
+		return true
+	default:
+		return false
+	}
+}
+
+// Host returns the target of the machine the compiler is running on.
+func Host() Target {
+	arch := runtime.GOARCH
+	os := runtime.GOOS
+
+	if arch == "386" {
+		arch = "i686"
+	}
+
+	t, err := Parse(arch + "-" + os)
+	if err != nil {
+		return Target{Arch: "x86_64", Vendor: "unknown", OS: "linux", Environment: "gnu"}
+	}
+
+	return t
+}
+
+// Triple returns the full LLVM target triple.
+func (t Target) Triple() string {
+	if t.Environment == "" {
+		return fmt.Sprintf("%s-%s-%s", t.Arch, t.Vendor, t.OS)
+	}
+
+	return fmt.Sprintf("%s-%s-%s-%s", t.Arch, t.Vendor, t.OS, t.Environment)
+}
+
+func (t Target) String() string {
+	return t.Triple()
+}
+
+// Is64Bit returns true for targets with 64 bit pointers.
+func (t Target) Is64Bit() bool {
+	return t.Arch == "x86_64" || t.Arch == "aarch64"
+}
+
+// PointerSize returns the size of pointers in bytes.
+func (t Target) PointerSize() int {
+	if t.Is64Bit() {
+		return 8
+	}
+
+	return 4
+}
+
+// Int64Align returns the alignment of 64 bit integers and floats in bytes, 32 bit x86 only aligns them to 4 bytes.
+func (t Target) Int64Align() int {
+	if t.Arch == "i686" && t.OS != "windows" {
+		return 4
+	}
+
+	return 8
+}
+
+// DataLayout returns the LLVM data layout string of the target.
+func (t Target) DataLayout() string {
+	mangling := "e"
+
+	switch t.OS {
+	case "darwin":
+		mangling = "o"
+	case "windows":
+		if t.Arch == "i686" {
+			mangling = "x"
+		} else {
+			mangling = "w"
+		}
+	}
+
+	switch t.Arch {
+	case "x86_64":
+		return fmt.Sprintf("e-m:%s-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128", mangling)
+	case "aarch64":
+		if t.OS == "darwin" {
+			return "e-m:o-i64:64-i128:128-n32:64-S128"
+		}
+
+		return fmt.Sprintf("e-m:%s-i8:8:32-i16:16:32-i64:64-i128:128-n32:64-S128", mangling)
+	case "i686":
+		if t.OS == "windows" {
+			return fmt.Sprintf("e-m:%s-p:32:32-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:32-n8:16:32-a:0:32-S32", mangling)
+		}
+
+		return fmt.Sprintf("e-m:%s-p:32:32-p270:32:32-p271:32:32-p272:64:64-f64:32:64-f80:32-n8:16:32-S128", mangling)
+	case "armv7":
+		return fmt.Sprintf("e-m:%s-p:32:32-Fi8-i64:64-v128:64:128-a:0:32-n32-S64", mangling)
+	default:
+		panic("target.DataLayout() - Invalid architecture")
+	}
+}
+
+// ArchName returns the architecture name used in conditional compilation attributes.
+func (t Target) ArchName() string {
+	switch t.Arch {
+	case "i686":
+		return "x86"
+	case "armv7":
+		return "arm"
+	default:
+		return t.Arch
+	}
+}
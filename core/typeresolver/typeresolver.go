@@ -3,9 +3,11 @@ package typeresolver
 import (
 	"fireball/core"
 	"fireball/core/ast"
+	"fireball/core/scanner"
 	"fireball/core/types"
 	"fireball/core/utils"
 	"fmt"
+	"strings"
 )
 
 type typeResolver struct {
@@ -13,12 +15,18 @@ type typeResolver struct {
 
 	reporter utils.Reporter
 	resolver utils.Resolver
+	decls    utils.Set[ast.Decl]
 }
 
 func Resolve(reporter utils.Reporter, resolver utils.Resolver, decls []ast.Decl) {
 	r := &typeResolver{
 		reporter: reporter,
 		resolver: resolver,
+		decls:    utils.NewSet[ast.Decl](),
+	}
+
+	for _, decl := range decls {
+		r.decls.Add(decl)
 	}
 
 	for _, decl := range decls {
@@ -29,9 +37,19 @@ func Resolve(reporter utils.Reporter, resolver utils.Resolver, decls []ast.Decl)
 // Declarations
 
 func (r *typeResolver) visitImpl(decl *ast.Impl) {
-	type_, _ := r.resolver.GetType(decl.Struct.Lexeme)
+	type_, path := r.resolver.GetType(decl.Struct.Lexeme)
+
+	if s, ok := type_.(*ast.Struct); ok && strings.Contains(decl.Struct.Lexeme, ".") {
+		// Methods are looked up in the project declaring the struct
+		r.reporter.Report(utils.Diagnostic{
+			Kind:    utils.ErrorKind,
+			Range:   core.TokenToRange(decl.Struct),
+			Message: fmt.Sprintf("Methods can't be implemented for struct '%s' of a different project.", decl.Struct),
+		})
 
-	if s, ok := type_.(*ast.Struct); ok {
+		decl.Type_ = nil
+	} else if ok {
+		r.checkVisible(s, decl.Struct, path)
 		decl.Type_ = s
 	} else {
 		r.reporter.Report(utils.Diagnostic{
@@ -48,7 +66,8 @@ func (r *typeResolver) visitImpl(decl *ast.Impl) {
 
 func (r *typeResolver) VisitType(type_ *types.Type) {
 	if v, ok := (*type_).(*types.UnresolvedType); ok {
-		if t, _ := r.resolver.GetType(v.Identifier.Lexeme); t != nil {
+		if t, path := r.resolver.GetType(v.Identifier.Lexeme); t != nil {
+			r.checkVisible(t, v.Identifier, path)
 			*type_ = t.WithRange(v.Range())
 		} else {
 			r.reporter.Report(utils.Diagnostic{
@@ -65,8 +84,32 @@ func (r *typeResolver) VisitType(type_ *types.Type) {
 		}
 	}
 
-	if *type_ != nil {
-		(*type_).AcceptTypesPtr(r)
+	// Structs and enums resolve their own types when their declaration is visited
+	switch (*type_).(type) {
+	case nil, *ast.Struct, *ast.Enum:
+		return
+	}
+
+	(*type_).AcceptTypesPtr(r)
+}
+
+// checkVisible reports an error if a private type declared in a different file is used.
+func (r *typeResolver) checkVisible(type_ types.Type, name scanner.Token, path string) {
+	pub := true
+
+	switch type_ := type_.(type) {
+	case *ast.Struct:
+		pub = type_.Pub || r.decls.Contains(type_)
+	case *ast.Enum:
+		pub = type_.Pub || r.decls.Contains(type_)
+	}
+
+	if !pub {
+		r.reporter.Report(utils.Diagnostic{
+			Kind:    utils.ErrorKind,
+			Range:   core.TokenToRange(name),
+			Message: fmt.Sprintf("Type '%s' is private to '%s'.", name, path),
+		})
 	}
 }
 
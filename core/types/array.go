@@ -2,6 +2,7 @@ package types
 
 import (
 	"fireball/core"
+	"fireball/core/target"
 	"fmt"
 )
 
@@ -24,12 +25,12 @@ func (a *ArrayType) Range() core.Range {
 	return a.range_
 }
 
-func (a *ArrayType) Size() int {
-	return int(a.Count) * a.Base.Size()
+func (a *ArrayType) Size(t target.Target) int {
+	return int(a.Count) * a.Base.Size(t)
 }
 
-func (a *ArrayType) Align() int {
-	return a.Base.Align()
+func (a *ArrayType) Align(t target.Target) int {
+	return a.Base.Align(t)
 }
 
 func (a *ArrayType) WithRange(range_ core.Range) Type {
@@ -50,7 +51,7 @@ func (a *ArrayType) Equals(other Type) bool {
 
 func (a *ArrayType) CanAssignTo(other Type) bool {
 	if v, ok := other.(*ArrayType); ok {
-		return a.Count == v.Count && a.Base.CanAssignTo(v.Base)
+		return a.Count == v.Count && CanReinterpret(a.Base, v.Base)
 	}
 
 	return false
@@ -1,5 +1,11 @@
 package types
 
+import (
+	"strings"
+)
+
+// Built-in attributes
+
 type ExternAttribute struct {
 	Name string
 }
@@ -8,5 +14,263 @@ type IntrinsicAttribute struct {
 	Name string
 }
 
+type ExportAttribute struct {
+	Name string
+}
+
 type InlineAttribute struct {
 }
+
+type UncheckedAttribute struct {
+}
+
+type TestAttribute struct {
+}
+
+type BenchAttribute struct {
+}
+
+type IfAttribute struct {
+	Condition string
+}
+
+type DeriveAttribute struct {
+	Traits []string
+}
+
+// CustomAttribute is an attribute declared by the project, arguments are either a string, int64 or bool and missing
+// optional arguments are nil.
+type CustomAttribute struct {
+	Name string
+	Args []any
+}
+
+// Schemas
+
+type AttributeTarget uint16
+
+const (
+	StructTarget AttributeTarget = 1 << iota
+	FieldTarget
+	EnumTarget
+	ImplTarget
+	FuncTarget
+	ParamTarget
+	StmtTarget
+	StaticAssertTarget
+
+	AllTargets = StructTarget | FieldTarget | EnumTarget | ImplTarget | FuncTarget | ParamTarget | StmtTarget | StaticAssertTarget
+)
+
+var targetNames = []string{"struct", "field", "enum", "impl", "function", "parameter", "statement", "static_assert"}
+
+// ParseAttributeTarget returns the target with the specified name as written in project configs.
+func ParseAttributeTarget(name string) (AttributeTarget, bool) {
+	for i, targetName := range targetNames {
+		if targetName == name {
+			return 1 << i, true
+		}
+	}
+
+	return 0, false
+}
+
+func (a AttributeTarget) String() string {
+	names := make([]string, 0, len(targetNames))
+
+	for i, name := range targetNames {
+		if a&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+type AttributeParamKind uint8
+
+const (
+	StringParam AttributeParamKind = iota
+	IntParam
+	BoolParam
+	IdentifierParam
+)
+
+var paramKindNames = []string{"string", "int", "bool", "identifier"}
+
+// ParseAttributeParamKind returns the parameter kind with the specified name as written in project configs.
+func ParseAttributeParamKind(name string) (AttributeParamKind, bool) {
+	for i, kindName := range paramKindNames {
+		if kindName == name {
+			return AttributeParamKind(i), true
+		}
+	}
+
+	return 0, false
+}
+
+func (a AttributeParamKind) String() string {
+	return paramKindNames[a]
+}
+
+type AttributeParam struct {
+	Name     string
+	Kind     AttributeParamKind
+	Optional bool
+
+	// Only allowed on the last parameter, collects all remaining arguments into a []any
+	Variadic bool
+}
+
+type AttributeSchema struct {
+	Name        string
+	Description string
+
+	Params  []AttributeParam
+	Targets AttributeTarget
+
+	// Creates the attribute value from validated arguments, custom attributes use CustomAttribute when nil
+	New func(args []any) any
+}
+
+func (a *AttributeSchema) Signature() string {
+	signature := strings.Builder{}
+
+	signature.WriteString("#[")
+	signature.WriteString(a.Name)
+
+	if len(a.Params) > 0 {
+		signature.WriteRune('(')
+
+		for i, param := range a.Params {
+			if i > 0 {
+				signature.WriteString(", ")
+			}
+
+			signature.WriteString(param.Name)
+			signature.WriteRune(' ')
+			signature.WriteString(param.Kind.String())
+
+			if param.Variadic {
+				signature.WriteString("...")
+			} else if param.Optional {
+				signature.WriteRune('?')
+			}
+		}
+
+		signature.WriteRune(')')
+	}
+
+	signature.WriteRune(']')
+	return signature.String()
+}
+
+func (a *AttributeSchema) Create(args []any) any {
+	if a.New != nil {
+		return a.New(args)
+	}
+
+	return CustomAttribute{Name: a.Name, Args: args}
+}
+
+var builtinAttributes = []AttributeSchema{
+	{
+		Name:        "Extern",
+		Description: "Declares a function implemented outside of Fireball, optionally under a different symbol name.",
+		Params:      []AttributeParam{{Name: "name", Kind: StringParam, Optional: true}},
+		Targets:     FuncTarget,
+		New: func(args []any) any {
+			name, _ := args[0].(string)
+			return ExternAttribute{Name: name}
+		},
+	},
+	{
+		Name:        "Intrinsic",
+		Description: "Implements a function with an LLVM intrinsic, optionally with a different name.",
+		Params:      []AttributeParam{{Name: "name", Kind: StringParam, Optional: true}},
+		Targets:     FuncTarget,
+		New: func(args []any) any {
+			name, _ := args[0].(string)
+			return IntrinsicAttribute{Name: name}
+		},
+	},
+	{
+		Name:        "Export",
+		Description: "Exports the function under an unmangled symbol name so it can be called from C, optionally under a different name.",
+		Params:      []AttributeParam{{Name: "name", Kind: StringParam, Optional: true}},
+		Targets:     FuncTarget,
+		New: func(args []any) any {
+			name, _ := args[0].(string)
+			return ExportAttribute{Name: name}
+		},
+	},
+	{
+		Name:        "Inline",
+		Description: "Always inlines the function.",
+		Targets:     FuncTarget,
+		New: func(_ []any) any {
+			return InlineAttribute{}
+		},
+	},
+	{
+		Name:        "Unchecked",
+		Description: "Disables overflow and safety checks inside the function.",
+		Targets:     FuncTarget,
+		New: func(_ []any) any {
+			return UncheckedAttribute{}
+		},
+	},
+	{
+		Name:        "Test",
+		Description: "Marks the function as a test run by 'fireball test', it fails if it panics.",
+		Targets:     FuncTarget,
+		New: func(_ []any) any {
+			return TestAttribute{}
+		},
+	},
+	{
+		Name:        "Bench",
+		Description: "Marks the function as a benchmark timed by 'fireball bench', store results in a static field so they are not optimized away.",
+		Targets:     FuncTarget,
+		New: func(_ []any) any {
+			return BenchAttribute{}
+		},
+	},
+	{
+		Name:        "If",
+		Description: "Only compiles the declaration when the condition holds for the target, like 'os == linux'.",
+		Params:      []AttributeParam{{Name: "condition", Kind: StringParam}},
+		Targets:     StructTarget | EnumTarget | ImplTarget | FuncTarget | StaticAssertTarget,
+		New: func(args []any) any {
+			condition, _ := args[0].(string)
+			return IfAttribute{Condition: condition}
+		},
+	},
+	{
+		Name:        "Derive",
+		Description: "Generates methods for the struct from its fields: 'Eq' (equals), 'Hash' (hash), 'Default' (default) and 'Debug' (debug).",
+		Params:      []AttributeParam{{Name: "traits", Kind: StringParam, Variadic: true}},
+		Targets:     StructTarget,
+		New: func(args []any) any {
+			values, _ := args[0].([]any)
+			traits := make([]string, 0, len(values))
+
+			for _, value := range values {
+				traits = append(traits, value.(string))
+			}
+
+			return DeriveAttribute{Traits: traits}
+		},
+	},
+}
+
+// GetBuiltinAttribute returns the schema of a built-in attribute or nil if none exists.
+func GetBuiltinAttribute(name string) *AttributeSchema {
+	for i := range builtinAttributes {
+		if builtinAttributes[i].Name == name {
+			return &builtinAttributes[i]
+		}
+	}
+
+	return nil
+}
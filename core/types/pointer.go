@@ -2,11 +2,15 @@ package types
 
 import (
 	"fireball/core"
+	"fireball/core/target"
 )
 
 type PointerType struct {
 	range_  core.Range
 	Pointee Type
+
+	// Const pointers can only be used to read the pointee
+	Const bool
 }
 
 func Pointer(pointee Type, range_ core.Range) *PointerType {
@@ -16,28 +20,37 @@ func Pointer(pointee Type, range_ core.Range) *PointerType {
 	}
 }
 
+func ConstPointer(pointee Type, range_ core.Range) *PointerType {
+	return &PointerType{
+		range_:  range_,
+		Pointee: pointee,
+		Const:   true,
+	}
+}
+
 func (p *PointerType) Range() core.Range {
 	return p.range_
 }
 
-func (p *PointerType) Size() int {
-	return 8
+func (p *PointerType) Size(t target.Target) int {
+	return t.PointerSize()
 }
 
-func (p *PointerType) Align() int {
-	return 8
+func (p *PointerType) Align(t target.Target) int {
+	return t.PointerSize()
 }
 
 func (p *PointerType) WithRange(range_ core.Range) Type {
 	return &PointerType{
 		range_:  range_,
 		Pointee: p.Pointee.WithRange(core.Range{}),
+		Const:   p.Const,
 	}
 }
 
 func (p *PointerType) Equals(other Type) bool {
 	if v, ok := other.(*PointerType); ok {
-		return p.Pointee.Equals(v.Pointee)
+		return p.Const == v.Const && p.Pointee.Equals(v.Pointee)
 	}
 
 	return false
@@ -45,7 +58,13 @@ func (p *PointerType) Equals(other Type) bool {
 
 func (p *PointerType) CanAssignTo(other Type) bool {
 	if v, ok := other.(*PointerType); ok {
-		return IsPrimitive(v.Pointee, Void) || p.Pointee.CanAssignTo(v.Pointee)
+		if CanReinterpret(p, v) {
+			return true
+		}
+
+		// Pointers to structs embedding the pointee are converted to point to the embedded field
+		embedder, ok := p.Pointee.(Embedder)
+		return ok && (!p.Const || v.Const) && embedder.Embeds(v.Pointee)
 	}
 
 	return false
@@ -60,5 +79,9 @@ func (p *PointerType) AcceptTypesPtr(visitor PtrVisitor) {
 }
 
 func (p *PointerType) String() string {
+	if p.Const {
+		return "*const " + p.Pointee.String()
+	}
+
 	return "*" + p.Pointee.String()
 }
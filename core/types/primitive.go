@@ -2,6 +2,7 @@ package types
 
 import (
 	"fireball/core"
+	"fireball/core/target"
 	"log"
 	"math"
 )
@@ -24,7 +25,7 @@ func (p *PrimitiveType) Range() core.Range {
 	return p.range_
 }
 
-func (p *PrimitiveType) Size() int {
+func (p *PrimitiveType) Size(_ target.Target) int {
 	switch p.Kind {
 	case Void:
 		return 0
@@ -47,8 +48,15 @@ func (p *PrimitiveType) Size() int {
 	}
 }
 
-func (p *PrimitiveType) Align() int {
-	return p.Size()
+func (p *PrimitiveType) Align(t target.Target) int {
+	size := p.Size(t)
+
+	// 64 bit values are not 8 byte aligned on all targets
+	if size == 8 {
+		return t.Int64Align()
+	}
+
+	return size
 }
 
 func (p *PrimitiveType) WithRange(range_ core.Range) Type {
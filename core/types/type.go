@@ -2,13 +2,15 @@ package types
 
 import (
 	"fireball/core"
+	"fireball/core/target"
 )
 
 type Type interface {
 	Range() core.Range
 
-	Size() int
-	Align() int
+	// Sizes and alignments depend on the target the type is laid out for
+	Size(t target.Target) int
+	Align(t target.Target) int
 
 	WithRange(range_ core.Range) Type
 
@@ -21,6 +23,40 @@ type Type interface {
 	String() string
 }
 
+// Embedder is implemented by types which embed other types, pointers to them can be used as pointers to the embedded
+// types.
+type Embedder interface {
+	Embeds(other Type) bool
+}
+
+// CanReinterpret returns true if a value of the type can be used as the other type without converting it. Unlike
+// CanAssignTo it is false for pointers to structs embedding the pointee, they need to point to the embedded field, so
+// it is used for values which are not converted one by one like array elements and pointees.
+func CanReinterpret(from, to Type) bool {
+	switch from := from.(type) {
+	case *PointerType:
+		if to, ok := to.(*PointerType); ok {
+			// Mutable pointers can be used as const ones but not the other way around
+			if from.Const && !to.Const {
+				return false
+			}
+
+			return IsPrimitive(to.Pointee, Void) || CanReinterpret(from.Pointee, to.Pointee)
+		}
+
+		return false
+
+	case *ArrayType:
+		if to, ok := to.(*ArrayType); ok {
+			return from.Count == to.Count && CanReinterpret(from.Base, to.Base)
+		}
+
+		return false
+	}
+
+	return from.CanAssignTo(to)
+}
+
 type Visitor interface {
 	VisitType(type_ Type)
 }
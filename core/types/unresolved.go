@@ -3,6 +3,7 @@ package types
 import (
 	"fireball/core"
 	"fireball/core/scanner"
+	"fireball/core/target"
 )
 
 type UnresolvedType struct {
@@ -21,11 +22,11 @@ func (u *UnresolvedType) Range() core.Range {
 	return u.range_
 }
 
-func (u *UnresolvedType) Size() int {
+func (u *UnresolvedType) Size(_ target.Target) int {
 	return 0
 }
 
-func (u *UnresolvedType) Align() int {
+func (u *UnresolvedType) Align(_ target.Target) int {
 	return 0
 }
 
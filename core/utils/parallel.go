@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Parallel calls fn for every index in [0, count) using at most jobs goroutines and returns once all calls finished.
+// A job count below one uses one goroutine per CPU.
+func Parallel(jobs, count int, fn func(i int)) {
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+
+	jobs = min(jobs, count)
+
+	if jobs <= 1 {
+		for i := 0; i < count; i++ {
+			fn(i)
+		}
+
+		return
+	}
+
+	indices := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for j := 0; j < jobs; j++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		indices <- i
+	}
+
+	close(indices)
+	wg.Wait()
+}
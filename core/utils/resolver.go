@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fireball/core/ast"
+	"fireball/core/target"
 	"fireball/core/types"
 )
 
@@ -11,4 +12,9 @@ type Resolver interface {
 	GetFunction(name string) (*ast.Func, string)
 
 	GetMethod(type_ types.Type, name string, static bool) (*ast.Func, string)
+
+	GetAttribute(name string) *types.AttributeSchema
+
+	// GetTarget returns the target types are laid out for
+	GetTarget() target.Target
 }
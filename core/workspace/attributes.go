@@ -0,0 +1,81 @@
+package workspace
+
+import (
+	"fireball/core/types"
+	"fmt"
+)
+
+type AttributeConfig struct {
+	Description string
+
+	// Names of the nodes the attribute can be used on, all when empty
+	Targets []string
+	Params  []AttributeParamConfig
+}
+
+type AttributeParamConfig struct {
+	Name     string
+	Type     string
+	Optional bool
+}
+
+// GetAttribute returns the schema of an attribute declared by the project or nil if none exists.
+func (p *Project) GetAttribute(name string) *types.AttributeSchema {
+	return p.attributes[name]
+}
+
+func createAttributeSchemas(configs map[string]AttributeConfig) (map[string]*types.AttributeSchema, error) {
+	schemas := make(map[string]*types.AttributeSchema, len(configs))
+
+	for name, config := range configs {
+		if types.GetBuiltinAttribute(name) != nil {
+			return nil, fmt.Errorf("attribute '%s' is already a built-in attribute", name)
+		}
+
+		schema := &types.AttributeSchema{
+			Name:        name,
+			Description: config.Description,
+			Params:      make([]types.AttributeParam, 0, len(config.Params)),
+		}
+
+		// Targets
+		if len(config.Targets) == 0 {
+			schema.Targets = types.AllTargets
+		}
+
+		for _, targetName := range config.Targets {
+			target, ok := types.ParseAttributeTarget(targetName)
+			if !ok {
+				return nil, fmt.Errorf("attribute '%s' has an unknown target '%s'", name, targetName)
+			}
+
+			schema.Targets |= target
+		}
+
+		// Params
+		optional := false
+
+		for _, paramConfig := range config.Params {
+			kind, ok := types.ParseAttributeParamKind(paramConfig.Type)
+			if !ok {
+				return nil, fmt.Errorf("attribute '%s' has a parameter '%s' with an unknown type '%s'", name, paramConfig.Name, paramConfig.Type)
+			}
+
+			if optional && !paramConfig.Optional {
+				return nil, fmt.Errorf("attribute '%s' has a required parameter '%s' after an optional one", name, paramConfig.Name)
+			}
+
+			optional = paramConfig.Optional
+
+			schema.Params = append(schema.Params, types.AttributeParam{
+				Name:     paramConfig.Name,
+				Kind:     kind,
+				Optional: paramConfig.Optional,
+			})
+		}
+
+		schemas[name] = schema
+	}
+
+	return schemas, nil
+}
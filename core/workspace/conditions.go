@@ -0,0 +1,241 @@
+package workspace
+
+import (
+	"errors"
+	"fireball/core"
+	"fireball/core/ast"
+	"fireball/core/scanner"
+	"fireball/core/types"
+	"fireball/core/utils"
+	"fmt"
+)
+
+// Properties returns the values conditional compilation attributes are evaluated against, flags of the profile
+// override the ones of the project config but neither can override the target properties.
+func (p *Project) Properties() map[string]string {
+	properties := make(map[string]string)
+
+	for name, value := range p.Config.Flags {
+		properties[name] = fmt.Sprint(value)
+	}
+
+	for name, value := range p.Profile.Flags {
+		properties[name] = fmt.Sprint(value)
+	}
+
+	properties["os"] = p.Target.OS
+	properties["arch"] = p.Target.ArchName()
+	properties["profile"] = p.Profile.Name
+
+	return properties
+}
+
+// filterDecls splits declarations into active ones and the ones compiled out by an 'If' attribute, methods are
+// removed from their implementations.
+func (f *File) filterDecls(decls []ast.Decl) ([]ast.Decl, []ast.Decl) {
+	properties := f.Project.Properties()
+
+	active := make([]ast.Decl, 0, len(decls))
+	var inactive []ast.Decl
+
+	for _, decl := range decls {
+		if !f.isActive(decl, properties) {
+			inactive = append(inactive, decl)
+			continue
+		}
+
+		if impl, ok := decl.(*ast.Impl); ok {
+			functions := make([]ast.Decl, 0, len(impl.Functions))
+
+			for _, function := range impl.Functions {
+				if f.isActive(function, properties) {
+					functions = append(functions, function)
+				} else {
+					inactive = append(inactive, function)
+				}
+			}
+
+			impl.Functions = functions
+		}
+
+		active = append(active, decl)
+	}
+
+	return active, inactive
+}
+
+func (f *File) isActive(decl ast.Decl, properties map[string]string) bool {
+	for _, attribute := range decl.(ast.Attributed).GetAttributes() {
+		if if_, ok := attribute.Value.(types.IfAttribute); ok && if_.Condition != "" {
+			value, err := evaluateCondition(if_.Condition, properties)
+
+			if err != nil {
+				f.Report(utils.Diagnostic{
+					Kind:    utils.ErrorKind,
+					Range:   core.TokenToRange(attribute.Name),
+					Message: fmt.Sprintf("Invalid condition: %s", err),
+				})
+
+				return false
+			}
+
+			if !value {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Evaluator
+
+// evaluateCondition evaluates a condition like 'os == linux && !(arch == x86)'. Identifiers on the left side of an
+// equality are property names and on the right side are values, a property on its own is true when it is set to
+// anything other than 'false'.
+func evaluateCondition(condition string, properties map[string]string) (bool, error) {
+	e := &evaluator{
+		scanner:    scanner.NewScanner(condition),
+		properties: properties,
+	}
+
+	e.advance()
+
+	value, err := e.or()
+	if err != nil {
+		return false, err
+	}
+
+	if e.next.Kind != scanner.Eof {
+		return false, fmt.Errorf("unexpected %s", describe(e.next))
+	}
+
+	return value, nil
+}
+
+type evaluator struct {
+	scanner    *scanner.Scanner
+	properties map[string]string
+
+	next scanner.Token
+}
+
+func (e *evaluator) or() (bool, error) {
+	value, err := e.and()
+
+	for err == nil && e.match(scanner.Or) {
+		var right bool
+		right, err = e.and()
+
+		value = value || right
+	}
+
+	return value, err
+}
+
+func (e *evaluator) and() (bool, error) {
+	value, err := e.unary()
+
+	for err == nil && e.match(scanner.And) {
+		var right bool
+		right, err = e.unary()
+
+		value = value && right
+	}
+
+	return value, err
+}
+
+func (e *evaluator) unary() (bool, error) {
+	if e.match(scanner.Bang) {
+		value, err := e.unary()
+		return !value, err
+	}
+
+	return e.primary()
+}
+
+func (e *evaluator) primary() (bool, error) {
+	// Group
+	if e.match(scanner.LeftParen) {
+		value, err := e.or()
+		if err != nil {
+			return false, err
+		}
+
+		if !e.match(scanner.RightParen) {
+			return false, errors.New("expected ')'")
+		}
+
+		return value, nil
+	}
+
+	// Literal
+	if e.match(scanner.True) {
+		return true, nil
+	}
+	if e.match(scanner.False) {
+		return false, nil
+	}
+
+	// Property
+	name := e.advance()
+
+	if name.Kind != scanner.Identifier {
+		return false, fmt.Errorf("expected property name but got %s", describe(name))
+	}
+
+	property, ok := e.properties[name.Lexeme]
+
+	if e.next.Kind != scanner.EqualEqual && e.next.Kind != scanner.BangEqual {
+		return ok && property != "false", nil
+	}
+
+	// Equality
+	op := e.advance()
+	value := e.advance()
+
+	switch value.Kind {
+	case scanner.Identifier, scanner.Number, scanner.True, scanner.False:
+	case scanner.String:
+		text, err := scanner.ParseString(value)
+		if err != nil {
+			return false, err
+		}
+
+		value.Lexeme = text
+
+	default:
+		return false, fmt.Errorf("expected value but got %s", describe(value))
+	}
+
+	if op.Kind == scanner.EqualEqual {
+		return property == value.Lexeme, nil
+	}
+
+	return property != value.Lexeme, nil
+}
+
+func (e *evaluator) match(kind scanner.TokenKind) bool {
+	if e.next.Kind == kind {
+		e.advance()
+		return true
+	}
+
+	return false
+}
+
+func (e *evaluator) advance() scanner.Token {
+	token := e.next
+	e.next = e.scanner.Next()
+
+	return token
+}
+
+func describe(token scanner.Token) string {
+	if token.Kind == scanner.Eof {
+		return "end of condition"
+	}
+
+	return fmt.Sprintf("'%s'", token)
+}
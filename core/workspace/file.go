@@ -4,6 +4,7 @@ import (
 	"fireball/core"
 	"fireball/core/ast"
 	"fireball/core/checker"
+	"fireball/core/deriver"
 	"fireball/core/parser"
 	"fireball/core/scanner"
 	"fireball/core/typeresolver"
@@ -21,6 +22,9 @@ type File struct {
 	Text  string
 	Decls []ast.Decl
 
+	// Declarations compiled out by conditional compilation attributes, only used for syntax highlighting
+	Inactive []ast.Decl
+
 	Types     map[string]types.Type
 	Functions map[string]*ast.Func
 
@@ -29,7 +33,8 @@ type File struct {
 	parseWaitGroup sync.WaitGroup
 	checkWaitGroup sync.WaitGroup
 
-	diagnostics []utils.Diagnostic
+	diagnostics      []utils.Diagnostic
+	diagnosticsMutex sync.Mutex
 }
 
 func (f *File) SetText(text string, parse bool) {
@@ -44,21 +49,27 @@ func (f *File) SetText(text string, parse bool) {
 		}
 
 		// Parse
-		f.Decls = parser.Parse(f, scanner.NewScanner(text))
+		f.parse()
 
 		f.CollectTypesAndFunctions()
 		typeresolver.Resolve(f, f.Project, f.Decls)
+		f.Decls = deriver.Derive(f, f.Project, f.Decls)
 
 		f.parseWaitGroup.Done()
 
 		// Check
-		for _, file := range f.Project.Files {
+		f.Project.parallel(f.Project.SortedFiles(), func(file *File) {
 			checker.Check(file, file.Project, file.Decls)
 			file.checkWaitGroup.Done()
-		}
+		})
 	}
 }
 
+func (f *File) parse() {
+	decls := parser.Parse(f, scanner.NewScanner(f.Text), f.Project.namespaces())
+	f.Decls, f.Inactive = f.filterDecls(decls)
+}
+
 func (f *File) EnsureParsed() {
 	f.parseWaitGroup.Wait()
 }
@@ -74,6 +85,8 @@ func (f *File) CollectTypesAndFunctions() {
 	for _, decl := range f.Decls {
 		if struct_, ok := decl.(*ast.Struct); ok {
 			// Struct
+			struct_.Namespace = f.Project.Namespace
+
 			if _, ok := typeMap[struct_.Name.Lexeme]; ok {
 				f.Report(utils.Diagnostic{
 					Kind:    utils.ErrorKind,
@@ -85,6 +98,8 @@ func (f *File) CollectTypesAndFunctions() {
 			}
 		} else if enum, ok := decl.(*ast.Enum); ok {
 			// Enum
+			enum.Namespace = f.Project.Namespace
+
 			if enum.Type == nil {
 				minValue := math.MaxInt
 				maxValue := math.MinInt
@@ -134,6 +149,8 @@ func (f *File) CollectTypesAndFunctions() {
 			}
 		} else if function, ok := decl.(*ast.Func); ok {
 			// Function
+			function.Namespace = f.Project.Namespace
+
 			if _, ok := functionMap[function.Name.Lexeme]; ok {
 				f.Report(utils.Diagnostic{
 					Kind:    utils.ErrorKind,
@@ -151,10 +168,16 @@ func (f *File) CollectTypesAndFunctions() {
 }
 
 func (f *File) Report(diag utils.Diagnostic) {
+	f.diagnosticsMutex.Lock()
+	defer f.diagnosticsMutex.Unlock()
+
 	f.diagnostics = append(f.diagnostics, diag)
 }
 
 func (f *File) FlushDiagnostics() []utils.Diagnostic {
+	f.diagnosticsMutex.Lock()
+	defer f.diagnosticsMutex.Unlock()
+
 	diagnostics := f.diagnostics
 	f.diagnostics = make([]utils.Diagnostic, 0)
 	return diagnostics
@@ -0,0 +1,164 @@
+package workspace
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Profile contains the resolved settings of a build profile.
+type Profile struct {
+	Name string
+
+	// Optimization level from 0 to 3
+	OptimizationLevel int
+
+	// Emit debug info and keep frame pointers
+	DebugInfo bool
+
+	// Emit overflow checked integer arithmetic
+	OverflowChecks bool
+
+	// Emit bounds, null pointer and division by zero checks
+	SafetyChecks bool
+
+	// Properties for conditional compilation attributes, merged over the flags of the project config
+	Flags map[string]any
+
+	// Folder inside build/ the artifacts of the profile are written to, or inside build/<triple>/ when cross compiling
+	Dir string
+}
+
+// ProfileConfig contains the settings of a profile in project.toml, unset settings are inherited.
+type ProfileConfig struct {
+	// Profile the unset settings are taken from, custom profiles inherit from 'debug' by default
+	Inherits string
+
+	OptimizationLevel *int
+	DebugInfo         *bool
+	OverflowChecks    *bool
+	SafetyChecks      *bool
+
+	Flags map[string]any
+
+	// Folder inside build/, defaults to the profile name
+	Dir string
+}
+
+// builtinProfile returns the settings of the 'debug' and 'release' profiles before they are changed by project.toml.
+func builtinProfile(name string) (Profile, bool) {
+	switch name {
+	case "debug":
+		return Profile{
+			Name:              name,
+			OptimizationLevel: 0,
+			DebugInfo:         true,
+			OverflowChecks:    true,
+			SafetyChecks:      true,
+		}, true
+
+	case "release":
+		return Profile{
+			Name:              name,
+			OptimizationLevel: 3,
+		}, true
+
+	default:
+		return Profile{}, false
+	}
+}
+
+// GetProfile returns the profile with the name. The 'debug' and 'release' profiles always exist, settings of
+// project.toml are applied over the inherited ones.
+func (p *Project) GetProfile(name string) (Profile, error) {
+	return p.resolveProfile(name, nil)
+}
+
+func (p *Project) resolveProfile(name string, stack []string) (Profile, error) {
+	if slices.Contains(stack, name) {
+		return Profile{}, fmt.Errorf("profile inheritance cycle: %s -> %s", strings.Join(stack, " -> "), name)
+	}
+
+	stack = append(stack, name)
+
+	// Inherited settings
+	config, ok := p.Config.Profile[name]
+	builtin, isBuiltin := builtinProfile(name)
+
+	var profile Profile
+
+	switch {
+	case config.Inherits != "":
+		parent, err := p.resolveProfile(config.Inherits, stack)
+		if err != nil {
+			return Profile{}, err
+		}
+
+		profile = parent
+
+	case isBuiltin:
+		profile = builtin
+
+		// Settings of the project apply to the built-in profiles
+		if p.Config.OverflowChecks != nil {
+			profile.OverflowChecks = *p.Config.OverflowChecks
+		}
+		if p.Config.SafetyChecks != nil {
+			profile.SafetyChecks = *p.Config.SafetyChecks
+		}
+
+	case ok:
+		parent, err := p.resolveProfile("debug", stack)
+		if err != nil {
+			return Profile{}, err
+		}
+
+		profile = parent
+
+	default:
+		return Profile{}, fmt.Errorf("unknown profile '%s'", name)
+	}
+
+	// Settings of the profile
+	profile.Name = name
+	profile.Dir = name
+	profile.Flags = maps.Clone(profile.Flags)
+
+	if config.OptimizationLevel != nil {
+		if *config.OptimizationLevel < 0 || *config.OptimizationLevel > 3 {
+			return Profile{}, fmt.Errorf("invalid profile.%s.OptimizationLevel %d, expected 0 to 3", name, *config.OptimizationLevel)
+		}
+
+		profile.OptimizationLevel = *config.OptimizationLevel
+	}
+
+	if config.DebugInfo != nil {
+		profile.DebugInfo = *config.DebugInfo
+	}
+	if config.OverflowChecks != nil {
+		profile.OverflowChecks = *config.OverflowChecks
+	}
+	if config.SafetyChecks != nil {
+		profile.SafetyChecks = *config.SafetyChecks
+	}
+
+	for flag, value := range config.Flags {
+		if profile.Flags == nil {
+			profile.Flags = make(map[string]any)
+		}
+
+		profile.Flags[flag] = value
+	}
+
+	if config.Dir != "" {
+		if !filepath.IsLocal(config.Dir) {
+			return Profile{}, fmt.Errorf("invalid profile.%s.Dir '%s', expected a folder inside build/", name, config.Dir)
+		}
+
+		profile.Dir = config.Dir
+	}
+
+	return profile, nil
+}
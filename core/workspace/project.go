@@ -4,30 +4,148 @@ import (
 	"errors"
 	"fireball/core/ast"
 	"fireball/core/checker"
-	"fireball/core/parser"
-	"fireball/core/scanner"
+	"fireball/core/deriver"
+	"fireball/core/target"
 	"fireball/core/typeresolver"
 	"fireball/core/types"
 	"fireball/core/utils"
+	"fmt"
 	"github.com/pelletier/go-toml/v2"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"unicode"
 )
 
 type Project struct {
 	Path   string
 	Config Config
 
+	// Build profile selecting the optimization level, checks and flags for conditional compilation attributes
+	Profile Profile
+
+	// Maximum number of files processed concurrently, zero uses one per CPU
+	Jobs int
+
+	// Machine the project is compiled for, sizes of types depend on it
+	Target target.Target
+
+	// Namespace the declarations of the project are mangled and referenced with, empty for the root project
+	Namespace string
+
+	// Projects the project depends on by the namespace used to reference them in code
+	Dependencies map[string]*Project
+
 	Files map[string]*File
+
+	// Guards the files map, lookups can happen concurrently from multiple files
+	filesMutex sync.RWMutex
+
+	attributes map[string]*types.AttributeSchema
 }
 
 type Config struct {
 	Name string
 	Src  string
+
+	// Version of the project, compared to the version required by projects depending on it
+	Version string
+
+	// Other Fireball projects used by the project, their declarations are accessed as 'name.Decl'
+	Dependencies map[string]DependencyConfig
+
+	// Overrides whether integer arithmetic is overflow checked in the built-in profiles, by default only in 'debug'
+	OverflowChecks *bool
+
+	// Overrides whether bounds, null pointer and division by zero checks are emitted in the built-in profiles, by
+	// default only in 'debug'
+	SafetyChecks *bool
+
+	// Kind of file the project is compiled to, defaults to an executable
+	Output OutputKind
+
+	// Target triple the project is compiled for, defaults to the host
+	Target string
+
+	// Libraries linked into the output by name, e.g. 'curl' for libcurl
+	Libraries []string
+
+	// Folders searched for libraries, relative to the project
+	LibraryPaths []string
+
+	// Additional arguments passed to the linker
+	LinkArgs []string
+
+	// Object files and static libraries linked into the output, relative to the project
+	Objects []string
+
+	// C source files compiled with the C compiler and linked into the output, relative to the project
+	CSources []string
+
+	// Overrides the paths of the external tools used to compile the project
+	Toolchain ToolchainConfig
+
+	// User defined properties that can be used in conditional compilation attributes
+	Flags map[string]any
+
+	// Build profiles by name, 'debug' and 'release' always exist and can be changed
+	Profile map[string]ProfileConfig
+
+	// Attributes declared by the project, their arguments are validated by the checker
+	Attributes map[string]AttributeConfig
+}
+
+type OutputKind string
+
+const (
+	ExecutableOutput    OutputKind = "exe"
+	StaticLibraryOutput OutputKind = "staticlib"
+	SharedLibraryOutput OutputKind = "sharedlib"
+)
+
+// DependencyConfig points at the folder of another Fireball project.
+type DependencyConfig struct {
+	// Path of the project folder, relative to the depending project
+	Path string
+
+	// Required version of the project, any version if empty
+	Version string
+}
+
+// ToolchainConfig contains paths of external tools, empty paths are searched for in the PATH.
+type ToolchainConfig struct {
+	LlvmLink string
+	Opt      string
+	Llc      string
+	Linker   string
+	Archiver string
+
+	// C compiler used to compile CSources
+	CCompiler string
+
+	// Folder containing the C runtime objects crt1.o, crti.o and crtn.o
+	Crt string
 }
 
 func NewProject(path string) (*Project, error) {
+	p, err := openProject(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dependencies
+	err = p.loadDependencies([]*Project{p}, make(map[string]*Project))
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// openProject reads and validates the project.toml file in the folder without loading dependencies.
+func openProject(path string) (*Project, error) {
 	// Check path
 	info, err := os.Stat(path)
 	if err != nil {
@@ -58,28 +176,289 @@ func NewProject(path string) (*Project, error) {
 		return nil, errors.New("invalid project src folder")
 	}
 
-	// Return
-	return &Project{
+	switch config.Output {
+	case "":
+		config.Output = ExecutableOutput
+	case ExecutableOutput, StaticLibraryOutput, SharedLibraryOutput:
+	default:
+		return nil, fmt.Errorf("invalid project output '%s', expected 'exe', 'staticlib' or 'sharedlib'", config.Output)
+	}
+
+	err = validateLinking(path, config)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := createAttributeSchemas(config.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	t := target.Host()
+
+	if config.Target != "" {
+		t, err = target.Parse(config.Target)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p := &Project{
 		Path:   path,
 		Config: config,
+		Target: t,
 
 		Files: make(map[string]*File),
-	}, nil
+
+		attributes: attributes,
+	}
+
+	// Profiles
+	for name := range config.Profile {
+		if _, err := p.GetProfile(name); err != nil {
+			return nil, err
+		}
+	}
+
+	p.Profile, err = p.GetProfile("debug")
+	if err != nil {
+		return nil, err
+	}
+
+	// Return
+	return p, nil
+}
+
+// validateLinking checks the settings of the files and libraries linked into the output, errors name the offending key.
+func validateLinking(path string, config Config) error {
+	for i, library := range config.Libraries {
+		if library == "" || strings.HasPrefix(library, "-") || strings.ContainsAny(library, "/\\") {
+			return fmt.Errorf("invalid Libraries[%d] '%s', expected the name of a library without the 'lib' prefix, e.g. 'curl'", i, library)
+		}
+	}
+
+	for i, folder := range config.LibraryPaths {
+		if info, err := os.Stat(filepath.Join(path, folder)); err != nil || !info.IsDir() {
+			return fmt.Errorf("invalid LibraryPaths[%d] '%s', folder does not exist", i, folder)
+		}
+	}
+
+	for i, object := range config.Objects {
+		if info, err := os.Stat(filepath.Join(path, object)); err != nil || info.IsDir() {
+			return fmt.Errorf("invalid Objects[%d] '%s', file does not exist", i, object)
+		}
+	}
+
+	for i, source := range config.CSources {
+		if filepath.Ext(source) != ".c" {
+			return fmt.Errorf("invalid CSources[%d] '%s', expected a '.c' file", i, source)
+		}
+
+		if info, err := os.Stat(filepath.Join(path, source)); err != nil || info.IsDir() {
+			return fmt.Errorf("invalid CSources[%d] '%s', file does not exist", i, source)
+		}
+	}
+
+	return nil
+}
+
+// loadDependencies opens the dependencies of the project recursively. The stack contains the projects currently being
+// loaded, used to detect cycles, and loaded contains all opened projects by their absolute path.
+func (p *Project) loadDependencies(stack []*Project, loaded map[string]*Project) error {
+	p.Dependencies = make(map[string]*Project, len(p.Config.Dependencies))
+
+	names := make([]string, 0, len(p.Config.Dependencies))
+
+	for name := range p.Config.Dependencies {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		config := p.Config.Dependencies[name]
+
+		// Validate config
+		if !isIdentifier(name) {
+			return fmt.Errorf("invalid dependency name '%s' in project '%s', it needs to be a valid identifier", name, p.Config.Name)
+		}
+		if config.Path == "" {
+			return fmt.Errorf("dependency '%s' of project '%s' has no path", name, p.Config.Name)
+		}
+
+		path, err := filepath.Abs(filepath.Join(p.Path, config.Path))
+		if err != nil {
+			return err
+		}
+
+		// Cycles
+		for i, project := range stack {
+			if abs, err := filepath.Abs(project.Path); err == nil && abs == path {
+				cycle := make([]string, 0, len(stack)-i+1)
+
+				for _, project := range stack[i:] {
+					cycle = append(cycle, project.Config.Name)
+				}
+
+				cycle = append(cycle, project.Config.Name)
+				return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+			}
+		}
+
+		// Open, projects used by multiple other projects are only opened once
+		dependency, ok := loaded[path]
+
+		if !ok {
+			dependency, err = openProject(path)
+			if err != nil {
+				return fmt.Errorf("dependency '%s' of project '%s': %w", name, p.Config.Name, err)
+			}
+
+			// Declarations are mangled with the project name so it needs to be unique
+			for _, project := range loaded {
+				if project.Config.Name == dependency.Config.Name {
+					return fmt.Errorf("projects '%s' and '%s' are both named '%s'", project.Path, dependency.Path, dependency.Config.Name)
+				}
+			}
+
+			dependency.Namespace = dependency.Config.Name
+			loaded[path] = dependency
+
+			err = dependency.loadDependencies(append(stack, dependency), loaded)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Version
+		if config.Version != "" && config.Version != dependency.Config.Version {
+			return fmt.Errorf("project '%s' requires version '%s' of dependency '%s' but '%s' has version '%s'", p.Config.Name, config.Version, name, dependency.Path, dependency.Config.Version)
+		}
+
+		p.Dependencies[name] = dependency
+	}
+
+	return nil
+}
+
+// Projects returns the project and all of its direct and indirect dependencies, dependencies come before the projects
+// using them.
+func (p *Project) Projects() []*Project {
+	projects := make([]*Project, 0, 1)
+	visited := make(map[*Project]bool)
+
+	var visit func(project *Project)
+
+	visit = func(project *Project) {
+		if visited[project] {
+			return
+		}
+
+		visited[project] = true
+
+		names := make([]string, 0, len(project.Dependencies))
+
+		for name := range project.Dependencies {
+			names = append(names, name)
+		}
+
+		slices.Sort(names)
+
+		for _, name := range names {
+			visit(project.Dependencies[name])
+		}
+
+		projects = append(projects, project)
+	}
+
+	visit(p)
+	return projects
+}
+
+// namespaces returns the names used to reference the dependencies in code.
+func (p *Project) namespaces() utils.Set[string] {
+	namespaces := utils.NewSet[string]()
+
+	for name := range p.Dependencies {
+		namespaces.Add(name)
+	}
+
+	return namespaces
+}
+
+// getDependency splits a qualified name like 'math.Vec2' into the dependency and the name inside of it.
+func (p *Project) getDependency(name string) (*Project, string, bool) {
+	namespace, rest, ok := strings.Cut(name, ".")
+	if !ok {
+		return nil, "", false
+	}
+
+	dependency, ok := p.Dependencies[namespace]
+	return dependency, rest, ok
+}
+
+// getProject returns the project with the namespace out of the project and its dependencies.
+func (p *Project) getProject(namespace string) *Project {
+	if p.Namespace == namespace {
+		return p
+	}
+
+	for _, dependency := range p.Dependencies {
+		if project := dependency.getProject(namespace); project != nil {
+			return project
+		}
+	}
+
+	return nil
+}
+
+func isIdentifier(name string) bool {
+	for i, char := range name {
+		if !(char == '_' || unicode.IsLetter(char) || (i > 0 && unicode.IsDigit(char))) {
+			return false
+		}
+	}
+
+	return name != ""
 }
 
 func NewEmptyProject(path, name string) *Project {
+	profile, _ := builtinProfile("debug")
+
 	return &Project{
+		Profile: profile,
+
 		Path: path,
 		Config: Config{
-			Name: name,
-			Src:  ".",
+			Name:   name,
+			Src:    ".",
+			Output: ExecutableOutput,
 		},
+		Target: target.Host(),
 
 		Files: make(map[string]*File),
 	}
 }
 
 func (p *Project) LoadFiles() error {
+	// Dependencies are loaded first, declarations of a project can use the ones of its dependencies
+	for _, project := range p.Projects() {
+		if project != p {
+			project.Profile = p.Profile
+			project.Jobs = p.Jobs
+			project.Target = p.Target
+		}
+
+		err := project.loadFiles()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Project) loadFiles() error {
 	// Get source files
 	files, err := p.GetSourceFiles()
 	if err != nil {
@@ -105,37 +484,81 @@ func (p *Project) LoadFiles() error {
 	}
 
 	// Parse
-	for _, file := range p.Files {
+	sorted := p.SortedFiles()
+
+	for _, file := range sorted {
 		file.parseWaitGroup.Add(1)
 		file.checkWaitGroup.Add(1)
 	}
 
-	for _, file := range p.Files {
-		decls := parser.Parse(file, scanner.NewScanner(file.Text))
-
-		file.Decls = decls
-	}
-
-	for _, file := range p.Files {
+	p.parallel(sorted, func(file *File) {
+		file.parse()
 		file.CollectTypesAndFunctions()
-	}
+	})
 
-	for _, file := range p.Files {
+	p.parallel(sorted, func(file *File) {
 		typeresolver.Resolve(file, p, file.Decls)
+	})
+
+	// Derive, needs the resolved types of all files. The declarations are only replaced once all files are derived
+	// because method lookups read the declarations of other files.
+	derived := make([][]ast.Decl, len(sorted))
+
+	utils.Parallel(p.Jobs, len(sorted), func(i int) {
+		derived[i] = deriver.Derive(sorted[i], p, sorted[i].Decls)
+	})
 
+	for i, file := range sorted {
+		file.Decls = derived[i]
 		file.parseWaitGroup.Done()
 	}
 
 	// Check
-	for _, file := range p.Files {
+	p.parallel(sorted, func(file *File) {
 		checker.Check(file, p, file.Decls)
 		file.checkWaitGroup.Done()
-	}
+	})
 
 	return nil
 }
 
+// SortedFiles returns the files of the project sorted by their path.
+func (p *Project) SortedFiles() []*File {
+	p.filesMutex.RLock()
+	defer p.filesMutex.RUnlock()
+
+	files := make([]*File, 0, len(p.Files))
+
+	for _, file := range p.Files {
+		files = append(files, file)
+	}
+
+	slices.SortFunc(files, func(a, b *File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return files
+}
+
+// parallel calls fn for every file using at most Jobs goroutines.
+func (p *Project) parallel(files []*File, fn func(file *File)) {
+	utils.Parallel(p.Jobs, len(files), func(i int) {
+		fn(files[i])
+	})
+}
+
+func (p *Project) GetTarget() target.Target {
+	return p.Target
+}
+
 func (p *Project) GetType(name string) (types.Type, string) {
+	if dependency, name, ok := p.getDependency(name); ok {
+		return dependency.GetType(name)
+	}
+
+	p.filesMutex.RLock()
+	defer p.filesMutex.RUnlock()
+
 	for _, file := range p.Files {
 		if v, ok := file.Types[name]; ok {
 			return v, file.Path
@@ -146,6 +569,13 @@ func (p *Project) GetType(name string) (types.Type, string) {
 }
 
 func (p *Project) GetFunction(name string) (*ast.Func, string) {
+	if dependency, name, ok := p.getDependency(name); ok {
+		return dependency.GetFunction(name)
+	}
+
+	p.filesMutex.RLock()
+	defer p.filesMutex.RUnlock()
+
 	for _, file := range p.Files {
 		if v, ok := file.Functions[name]; ok {
 			return v, file.Path
@@ -156,9 +586,27 @@ func (p *Project) GetFunction(name string) (*ast.Func, string) {
 }
 
 func (p *Project) GetMethod(type_ types.Type, name string, static bool) (*ast.Func, string) {
+	// Structs are compared by name, different structs with the same layout are equal
+	struct_, ok := type_.(*ast.Struct)
+	if !ok {
+		return nil, ""
+	}
+
+	// Methods are declared in the project of the struct
+	if struct_.Namespace != p.Namespace {
+		if project := p.getProject(struct_.Namespace); project != nil && project != p {
+			return project.GetMethod(type_, name, static)
+		}
+
+		return nil, ""
+	}
+
+	p.filesMutex.RLock()
+	defer p.filesMutex.RUnlock()
+
 	for _, file := range p.Files {
 		for _, decl := range file.Decls {
-			if impl, ok := decl.(*ast.Impl); ok && impl.Type_ != nil && impl.Type_.Equals(type_) {
+			if impl, ok := decl.(*ast.Impl); ok && impl.Type_ != nil && impl.Type_.Name.Lexeme == struct_.Name.Lexeme {
 				function := impl.GetMethod(name, static)
 
 				if function != nil {
@@ -172,6 +620,9 @@ func (p *Project) GetMethod(type_ types.Type, name string, static bool) (*ast.Fu
 }
 
 func (p *Project) GetOrCreateFile(path string) *File {
+	p.filesMutex.Lock()
+	defer p.filesMutex.Unlock()
+
 	if file, ok := p.Files[path]; ok {
 		return file
 	}
@@ -201,17 +652,19 @@ func (p *Project) RemoveFile(path string) bool {
 	// Find file
 	if _, ok := p.Files[path]; ok {
 		// Delete file
+		p.filesMutex.Lock()
 		delete(p.Files, path)
+		p.filesMutex.Unlock()
 
 		// Check the rest of the files
 		for _, file := range p.Files {
 			file.checkWaitGroup.Add(1)
 		}
 
-		for _, file := range p.Files {
+		p.parallel(p.SortedFiles(), func(file *File) {
 			checker.Check(file, p, file.Decls)
 			file.checkWaitGroup.Done()
-		}
+		})
 
 		// Return true
 		return true
@@ -14,9 +14,10 @@ type item struct {
 
 	bitField bool
 
-	token    string
-	ast      bool
-	noString bool
+	token      string
+	ast        bool
+	noString   bool
+	attributes bool
 }
 
 type field struct {
@@ -28,18 +29,26 @@ var decls = []item{
 	{
 		name: "Struct",
 		fields: []field{
+			{name: "Docs", type_: "string"},
+			{name: "Pub", type_: "bool"},
+			{name: "Namespace", type_: "string"},
 			{name: "Name", type_: "Token"},
 			{name: "StaticFields", type_: "[]Field"},
 			{name: "Fields", type_: "[]Field"},
 			{name: "Type", type_: "Type"},
 		},
-		token: "Name",
-		ast:   true,
+		token:      "Name",
+		ast:        true,
+		attributes: true,
 	},
 	{
 		name: "Field",
 		fields: []field{
+			{name: "Docs", type_: "string"},
+			{name: "Attributes", type_: "[]Attribute"},
 			{name: "Parent", type_: "*Struct"},
+			{name: "Pub", type_: "bool"},
+			{name: "Embedded", type_: "bool"},
 			{name: "Name", type_: "Token"},
 			{name: "Type", type_: "Type"},
 		},
@@ -51,24 +60,31 @@ var decls = []item{
 			{name: "Struct", type_: "Token"},
 			{name: "Type_", type_: "*Struct"},
 			{name: "Functions", type_: "[]Decl"},
+			{name: "Derived", type_: "bool"},
 		},
-		token: "Struct",
-		ast:   true,
+		token:      "Struct",
+		ast:        true,
+		attributes: true,
 	},
 	{
 		name: "Enum",
 		fields: []field{
+			{name: "Docs", type_: "string"},
+			{name: "Pub", type_: "bool"},
+			{name: "Namespace", type_: "string"},
 			{name: "Name", type_: "Token"},
 			{name: "Type", type_: "Type"},
 			{name: "InferType", type_: "bool"},
 			{name: "Cases", type_: "[]EnumCase"},
 		},
-		token: "Name",
-		ast:   true,
+		token:      "Name",
+		ast:        true,
+		attributes: true,
 	},
 	{
 		name: "EnumCase",
 		fields: []field{
+			{name: "Docs", type_: "string"},
 			{name: "Name", type_: "Token"},
 			{name: "Value", type_: "int"},
 			{name: "InferValue", type_: "bool"},
@@ -78,33 +94,50 @@ var decls = []item{
 	{
 		name: "Func",
 		fields: []field{
-			{name: "Attributes", type_: "[]any"},
+			{name: "Docs", type_: "string"},
+			{name: "Pub", type_: "bool"},
+			{name: "Namespace", type_: "string"},
 			{name: "Flags", type_: "FuncFlags"},
 			{name: "Name", type_: "Token"},
 			{name: "Params", type_: "[]Param"},
 			{name: "Returns", type_: "Type"},
 			{name: "Body", type_: "[]Stmt"},
 		},
-		token:    "Name",
-		ast:      true,
-		noString: true,
+		token:      "Name",
+		ast:        true,
+		attributes: true,
+		noString:   true,
 	},
 	{
 		name: "FuncFlags",
 		cases: []string{
 			"Static",
 			"Variadic",
+			"Bound",
+			"Const",
 		},
 		bitField: true,
 	},
 	{
 		name: "Param",
 		fields: []field{
+			{name: "Attributes", type_: "[]Attribute"},
 			{name: "Name", type_: "Token"},
 			{name: "Type", type_: "Type"},
 		},
 		ast: false,
 	},
+	{
+		name: "StaticAssert",
+		fields: []field{
+			{name: "Token_", type_: "Token"},
+			{name: "Condition", type_: "Expr"},
+			{name: "Message", type_: "Token"},
+		},
+		token:      "Token_",
+		ast:        true,
+		attributes: true,
+	},
 }
 
 var stmts = []item{
@@ -114,8 +147,9 @@ var stmts = []item{
 			{name: "Token_", type_: "Token"},
 			{name: "Stmts", type_: "[]Stmt"},
 		},
-		token: "Token_",
-		ast:   true,
+		token:      "Token_",
+		ast:        true,
+		attributes: true,
 	},
 	{
 		name: "Expression",
@@ -123,8 +157,9 @@ var stmts = []item{
 			{name: "Token_", type_: "Token"},
 			{name: "Expr", type_: "Expr"},
 		},
-		token: "Token_",
-		ast:   true,
+		token:      "Token_",
+		ast:        true,
+		attributes: true,
 	},
 	{
 		name: "Variable",
@@ -133,9 +168,24 @@ var stmts = []item{
 			{name: "Name", type_: "Token"},
 			{name: "Initializer", type_: "Expr"},
 			{name: "InferType", type_: "bool"},
+			{name: "Immutable", type_: "bool"},
 		},
-		token: "Name",
-		ast:   true,
+		token:      "Name",
+		ast:        true,
+		attributes: true,
+	},
+	{
+		name: "Destructure",
+		fields: []field{
+			{name: "Token_", type_: "Token"},
+			{name: "Type", type_: "Type"},
+			{name: "Array", type_: "bool"},
+			{name: "Initializer", type_: "Expr"},
+			{name: "Bindings", type_: "[]Stmt"},
+		},
+		token:      "Token_",
+		ast:        true,
+		attributes: true,
 	},
 	{
 		name: "If",
@@ -145,8 +195,9 @@ var stmts = []item{
 			{name: "Then", type_: "Stmt"},
 			{name: "Else", type_: "Stmt"},
 		},
-		token: "Token_",
-		ast:   true,
+		token:      "Token_",
+		ast:        true,
+		attributes: true,
 	},
 	{
 		name: "For",
@@ -157,8 +208,9 @@ var stmts = []item{
 			{name: "Increment", type_: "Expr"},
 			{name: "Body", type_: "Stmt"},
 		},
-		token: "Token_",
-		ast:   true,
+		token:      "Token_",
+		ast:        true,
+		attributes: true,
 	},
 	{
 		name: "Return",
@@ -166,24 +218,38 @@ var stmts = []item{
 			{name: "Token_", type_: "Token"},
 			{name: "Expr", type_: "Expr"},
 		},
-		token: "Token_",
-		ast:   true,
+		token:      "Token_",
+		ast:        true,
+		attributes: true,
 	},
 	{
 		name: "Break",
 		fields: []field{
 			{name: "Token_", type_: "Token"},
 		},
-		token: "Token_",
-		ast:   true,
+		token:      "Token_",
+		ast:        true,
+		attributes: true,
 	},
 	{
 		name: "Continue",
 		fields: []field{
 			{name: "Token_", type_: "Token"},
 		},
-		token: "Token_",
-		ast:   true,
+		token:      "Token_",
+		ast:        true,
+		attributes: true,
+	},
+	{
+		name: "StaticAssertStmt",
+		fields: []field{
+			{name: "Token_", type_: "Token"},
+			{name: "Condition", type_: "Expr"},
+			{name: "Message", type_: "Token"},
+		},
+		token:      "Token_",
+		ast:        true,
+		attributes: true,
 	},
 }
 
@@ -273,6 +339,27 @@ var exprs = []item{
 		token: "Op",
 		ast:   true,
 	},
+	{
+		name: "IfExpr",
+		fields: []field{
+			{name: "Token_", type_: "Token"},
+			{name: "Condition", type_: "Expr"},
+			{name: "Then", type_: "Expr"},
+			{name: "Else", type_: "Expr"},
+		},
+		token: "Token_",
+		ast:   true,
+	},
+	{
+		name: "BlockExpr",
+		fields: []field{
+			{name: "Token_", type_: "Token"},
+			{name: "Stmts", type_: "[]Stmt"},
+			{name: "Value", type_: "Expr"},
+		},
+		token: "Token_",
+		ast:   true,
+	},
 	{
 		name: "Identifier",
 		fields: []field{
@@ -309,6 +396,7 @@ var exprs = []item{
 			{name: "Token_", type_: "Token"},
 			{name: "Target", type_: "Type"},
 			{name: "Expr", type_: "Expr"},
+			{name: "Implicit", type_: "bool"},
 		},
 		token: "Token_",
 		ast:   true,
@@ -322,6 +410,16 @@ var exprs = []item{
 		token: "Name",
 		ast:   true,
 	},
+	{
+		name: "Assert",
+		fields: []field{
+			{name: "Token_", type_: "Token"},
+			{name: "Condition", type_: "Expr"},
+			{name: "Message", type_: "Token"},
+		},
+		token: "Token_",
+		ast:   true,
+	},
 	{
 		name: "Call",
 		fields: []field{
@@ -347,6 +445,7 @@ var exprs = []item{
 		fields: []field{
 			{name: "Value", type_: "Expr"},
 			{name: "Name", type_: "Token"},
+			{name: "Implicit", type_: "bool"},
 		},
 		token: "Name",
 		ast:   true,
@@ -447,6 +546,10 @@ func generate(w *writer, kind string, items []item) {
 				w.write("")
 			}
 
+			if item.attributes {
+				w.write("Attributes []Attribute")
+			}
+
 			for _, field := range item.fields {
 				type_ := field.type_
 
@@ -565,6 +668,19 @@ func generate(w *writer, kind string, items []item) {
 					w.write("")
 				}
 
+				// Attributes
+				if item.attributes {
+					w.write("%s GetAttributes() []Attribute {", method)
+					w.write("return %c.Attributes", short)
+					w.write("}")
+					w.write("")
+
+					w.write("%s SetAttributes(attributes []Attribute) {", method)
+					w.write("%c.Attributes = attributes", short)
+					w.write("}")
+					w.write("")
+				}
+
 				// SetChildrenParent
 				w.write("%s SetChildrenParent() {", method)
 